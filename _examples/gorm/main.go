@@ -58,14 +58,8 @@ func main() {
 			Columns: []gorm.Column{
 				{
 					Name: "created_at",
-					// For SQLite the placeholder must be wrapped with `datetime()`
-					Placeholder: func(gorm.Column) string {
-						return "datetime(?)"
-					},
-					// For SQLite the column name must be wrapped with `datetime()`
-					Reference: func(c gorm.Column) (string, []interface{}) {
-						return fmt.Sprintf("datetime(%v)", c.Name), nil
-					},
+					// Compared as time values, for SQLite wrapped with `datetime()`
+					Time: true,
 				},
 			},
 		}),
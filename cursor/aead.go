@@ -0,0 +1,81 @@
+package cursor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+var ErrDecrypt = errors.New("cursor: unable to decrypt")
+
+// Encrypts the input with aead and a random nonce, to prevent clients from reading (and forging) cursors.
+// Decode accepts inputs encrypted with aead or any of the decryptionAEADs (allows rotating keys),
+// returns ErrDecrypt otherwise
+func AEAD(aead cipher.AEAD, decryptionAEADs ...cipher.AEAD) Encoder {
+	aeads := make([]cipher.AEAD, 0, len(decryptionAEADs)+1)
+	aeads = append(aeads, aead)
+	aeads = append(aeads, decryptionAEADs...)
+
+	return aeadEncoder{aeads}
+}
+
+// AEAD using AES-GCM, keys must be 16, 24 or 32 bytes long
+func AESGCM(key []byte, decryptionKeys ...[]byte) (Encoder, error) {
+	aeads := make([]cipher.AEAD, 0, len(decryptionKeys)+1)
+	for _, k := range append([][]byte{key}, decryptionKeys...) {
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		aeads = append(aeads, aead)
+	}
+
+	return AEAD(aeads[0], aeads[1:]...), nil
+}
+
+type aeadEncoder struct {
+	aeads []cipher.AEAD
+}
+
+func (a aeadEncoder) Encode(input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	aead := a.aeads[0]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(input)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, input, nil), nil
+}
+
+func (a aeadEncoder) Decode(encoded []byte) ([]byte, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	for _, aead := range a.aeads {
+		if len(encoded) < aead.NonceSize()+aead.Overhead() {
+			continue
+		}
+
+		nonce, ciphertext := encoded[:aead.NonceSize()], encoded[aead.NonceSize():]
+
+		decoded, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, ErrDecrypt
+}
@@ -11,6 +11,9 @@ type Cursor struct {
 	Limit int
 	Type  Type
 	Value interface{}
+	// Optional bound on the other side of Value: the traversal stops before reaching Until (excluded).
+	// Allows to paginate within a window (ex: Relay's `first` with both `after` and `before`)
+	Until interface{}
 }
 
 // Used to transform the driver cursor representation (can be any type, most likely a literal, array or map)
@@ -1,9 +1,12 @@
 package cursor
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func testRoundTrip(t *testing.T, marshaller Marshaller, input interface{}, check func(interface{})) {
@@ -114,3 +117,148 @@ func TestChainMsgPackBase64Reverse(t *testing.T) {
 func TestChainMsgPackReverseBase64(t *testing.T) {
 	testRoutine(t, Chain(MsgPack(), reverse{}, Base64(base64.StdEncoding)))
 }
+
+func TestChainMsgPackHMACBase64(t *testing.T) {
+	testRoutine(t, Chain(MsgPack(), HMAC([]byte("key"), sha256.New), Base64(base64.StdEncoding)))
+}
+
+func TestHMAC_Tampered(t *testing.T) {
+	e := HMAC([]byte("key"), sha256.New)
+
+	encoded, err := e.Encode([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	decoded, err := e.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, decoded)
+
+	encoded[0] = 4
+	_, err = e.Decode(encoded)
+	assert.Equal(t, ErrInvalidMAC, err)
+
+	_, err = e.Decode([]byte{1, 2, 3})
+	assert.Equal(t, ErrInvalidMAC, err)
+}
+
+func TestHMAC_Rotation(t *testing.T) {
+	old := HMAC([]byte("old"), sha256.New)
+	current := HMAC([]byte("new"), sha256.New, []byte("old"))
+	other := HMAC([]byte("other"), sha256.New)
+
+	encoded, err := old.Encode([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	decoded, err := current.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, decoded)
+
+	_, err = other.Decode(encoded)
+	assert.Equal(t, ErrInvalidMAC, err)
+
+	encoded, err = current.Encode([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	_, err = old.Decode(encoded)
+	assert.Equal(t, ErrInvalidMAC, err)
+}
+
+func TestChainMsgPackAESGCMBase64(t *testing.T) {
+	e, err := AESGCM([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	testRoutine(t, Chain(MsgPack(), e, Base64(base64.StdEncoding)))
+}
+
+func TestAESGCM(t *testing.T) {
+	e, err := AESGCM([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	encoded1, err := e.Encode([]byte("created_at"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded1), "created_at")
+
+	encoded2, err := e.Encode([]byte("created_at"))
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded1, encoded2, "nonce must be random")
+
+	decoded, err := e.Decode(encoded1)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("created_at"), decoded)
+
+	encoded1[len(encoded1)-1]++
+	_, err = e.Decode(encoded1)
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = e.Decode([]byte{1, 2, 3})
+	assert.Equal(t, ErrDecrypt, err)
+}
+
+func TestAESGCM_Rotation(t *testing.T) {
+	old, err := AESGCM([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	current, err := AESGCM([]byte("fedcba9876543210fedcba9876543210"), []byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	encoded, err := old.Encode([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	decoded, err := current.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, decoded)
+
+	encoded, err = current.Encode([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	_, err = old.Decode(encoded)
+	assert.Equal(t, ErrDecrypt, err)
+}
+
+func TestAESGCM_InvalidKey(t *testing.T) {
+	_, err := AESGCM([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestEnvelope(t *testing.T) {
+	testRoutine(t, Envelope(MsgPack(), EnvelopeOptions{Version: 1, TTL: time.Hour}))
+}
+
+func TestChainEnvelopeBase64(t *testing.T) {
+	testRoutine(t, Chain(Envelope(MsgPack(), EnvelopeOptions{Version: 1}), Base64(base64.StdEncoding)))
+}
+
+func TestEnvelope_Expired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := Envelope(MsgPack(), EnvelopeOptions{
+		TTL: time.Minute,
+		Now: func() time.Time {
+			return now
+		},
+	})
+
+	encoded, err := m.Marshal("hey")
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	v, err := m.Unmarshal(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "hey", v)
+
+	now = now.Add(time.Second)
+	_, err = m.Unmarshal(encoded)
+	assert.Equal(t, ErrExpired, err)
+}
+
+func TestEnvelope_Version(t *testing.T) {
+	v1 := Envelope(MsgPack(), EnvelopeOptions{Version: 1})
+	v2 := Envelope(MsgPack(), EnvelopeOptions{Version: 2})
+
+	encoded, err := v1.Marshal("hey")
+	require.NoError(t, err)
+
+	_, err = v2.Unmarshal(encoded)
+	assert.Equal(t, ErrVersionMismatch, err)
+
+	_, err = v2.Unmarshal([]byte{0xff})
+	assert.Equal(t, ErrMalformedEnvelope, err)
+}
@@ -0,0 +1,83 @@
+package cursor
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var (
+	ErrExpired           = errors.New("cursor: expired")
+	ErrVersionMismatch   = errors.New("cursor: incompatible version")
+	ErrMalformedEnvelope = errors.New("cursor: malformed envelope")
+)
+
+type EnvelopeOptions struct {
+	// Version of the cursors schema, must be changed whenever the cursor values change
+	// (ex: when changing the columns of a paginator), see sqlbase.ColumnsVersion.
+	// Cursors of another version are rejected with ErrVersionMismatch
+	Version uint64
+	// Cursors issued more than TTL ago are rejected with ErrExpired, never expire when 0
+	TTL time.Duration
+	// Defaults to time.Now
+	Now func() time.Time
+}
+
+// Embeds the version and issue time into the cursors marshalled by m
+func Envelope(m Marshaller, o EnvelopeOptions) Marshaller {
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	return envelope{m: m, o: o}
+}
+
+type envelope struct {
+	m Marshaller
+	o EnvelopeOptions
+}
+
+func (e envelope) Marshal(input interface{}) ([]byte, error) {
+	data, err := e.m.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	header := make([]byte, 2*binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, e.o.Version)
+	n += binary.PutVarint(header[n:], e.o.Now().Unix())
+
+	return append(header[:n], data...), nil
+}
+
+func (e envelope) Unmarshal(encoded []byte) (interface{}, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	version, n := binary.Uvarint(encoded)
+	if n <= 0 {
+		return nil, ErrMalformedEnvelope
+	}
+	encoded = encoded[n:]
+
+	issuedAt, n := binary.Varint(encoded)
+	if n <= 0 {
+		return nil, ErrMalformedEnvelope
+	}
+	encoded = encoded[n:]
+
+	if version != e.o.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	if e.o.TTL > 0 && e.o.Now().Sub(time.Unix(issuedAt, 0)) > e.o.TTL {
+		return nil, ErrExpired
+	}
+
+	return e.m.Unmarshal(encoded)
+}
@@ -0,0 +1,70 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"errors"
+	"hash"
+)
+
+var ErrInvalidMAC = errors.New("cursor: invalid mac")
+
+// Appends the MAC of the input computed with key, to prevent clients from forging cursors.
+// Decode accepts a MAC computed with key or any of the verificationKeys (allows rotating keys),
+// returns ErrInvalidMAC otherwise
+func HMAC(key []byte, hash func() hash.Hash, verificationKeys ...[]byte) Encoder {
+	keys := make([][]byte, 0, len(verificationKeys)+1)
+	keys = append(keys, key)
+	keys = append(keys, verificationKeys...)
+
+	return hmacEncoder{
+		keys: keys,
+		hash: hash,
+	}
+}
+
+type hmacEncoder struct {
+	keys [][]byte
+	hash func() hash.Hash
+}
+
+func (h hmacEncoder) mac(key, input []byte) []byte {
+	m := hmac.New(h.hash, key)
+	m.Write(input)
+
+	return m.Sum(nil)
+}
+
+func (h hmacEncoder) Encode(input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	mac := h.mac(h.keys[0], input)
+
+	encoded := make([]byte, 0, len(input)+len(mac))
+	encoded = append(encoded, input...)
+	encoded = append(encoded, mac...)
+
+	return encoded, nil
+}
+
+func (h hmacEncoder) Decode(encoded []byte) ([]byte, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	size := h.hash().Size()
+	if len(encoded) <= size {
+		return nil, ErrInvalidMAC
+	}
+
+	input, mac := encoded[:len(encoded)-size], encoded[len(encoded)-size:]
+
+	for _, key := range h.keys {
+		if hmac.Equal(mac, h.mac(key, input)) {
+			return input, nil
+		}
+	}
+
+	return nil, ErrInvalidMAC
+}
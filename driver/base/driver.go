@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/raphaelvigee/go-paginate/cursor"
@@ -10,70 +11,121 @@ import (
 type ExecutorFactoryArgs struct {
 	Input  interface{}
 	Cursor cursor.Cursor
+	// Destination passed to Driver.PaginateInto, nil otherwise (see IntoExecutor)
+	Dst interface{}
 }
 
-var ErrNoResult = errors.New("no result")
+var (
+	ErrNoResult         = errors.New("no result")
+	ErrUntilUnsupported = errors.New("cursor until is not supported by the executor")
+)
 
+// Can optionally implement driver.TotalCounter
 type Executor interface {
 	// Must throw ErrNoResult if no result can be found
-	TakeFirst() (interface{}, error)
-	CountPrevious(cvalue interface{}) (int64, error)
-	FindNext(cvalue interface{}, isFirst bool) ([]interface{}, error)
+	TakeFirst(ctx context.Context) (interface{}, error)
+	// Counts the rows before cvalue in the order of the traversal, cvalue included
+	CountPrevious(ctx context.Context, cvalue interface{}) (int64, error)
+	FindNext(ctx context.Context, cvalue interface{}, isFirst bool) ([]interface{}, error)
 
 	Page(sm interface{}, em interface{}) driver.Executor
 }
 
+// Can be implemented by the Executor to support cursor.Cursor.Until, the returned Executor must
+// exclude the rows at or after until (in the order of the traversal) from TakeFirst and FindNext
+type UntilExecutor interface {
+	Until(until interface{}) Executor
+}
+
+// Can be implemented by the Executor to replace TakeFirst, CountPrevious and FindNext by a single call
+type PageFinder interface {
+	// Returns the same rows as FindNext (from the first row, included, when cvalue is nil),
+	// along with whether any row precedes cvalue (cvalue included)
+	FindPage(ctx context.Context, cvalue interface{}) ([]interface{}, bool, error)
+}
+
+// Can be implemented by the Executor to encode the cursors of its rows instead of Driver.CursorEncoder
+// (ex: when the values depend on the input)
+type CursorEncodeExecutor interface {
+	CursorEncode(input interface{}) (interface{}, error)
+}
+
+// Can be implemented by the Executor to support Driver.PaginateInto
+type IntoExecutor interface {
+	// When true, FindNext (or FindPage) also scans the rows it returns into ExecutorFactoryArgs.Dst, in the same order
+	ScansInto() bool
+}
+
 type Driver struct {
 	driver.CursorEncoder
 
 	ExecutorFactory func(ExecutorFactoryArgs) Executor
+
+	// Returns the rows of Before pages in the natural order (instead of the order of the traversal),
+	// with the PageInfo relative to it: HasNextPage reports the rows following the page in the natural order,
+	// StartCursor is the cursor of its first row, and Cursor(0) as well.
+	// The pages implement driver.NaturalOrderer
+	NaturalOrder bool
 }
 
 var _ driver.Driver = (*Driver)(nil)
+var _ driver.IntoPaginator = (*Driver)(nil)
+
+func (d Driver) Paginate(ctx context.Context, c cursor.Cursor, input interface{}) (driver.Page, error) {
+	return d.paginate(ctx, c, input, nil)
+}
+
+// Scans the rows of the page into dst along with the values of the cursors when the executor supports it
+// (see IntoExecutor), queries them through the page otherwise
+func (d Driver) PaginateInto(ctx context.Context, c cursor.Cursor, input interface{}, dst interface{}) (driver.Page, error) {
+	return d.paginate(ctx, c, input, dst)
+}
+
+func (d Driver) paginate(ctx context.Context, c cursor.Cursor, input interface{}, dst interface{}) (driver.Page, error) {
+	limit := c.Limit
+	if limit < 1 {
+		return nil, &driver.Error{Kind: driver.ErrLimitOutOfRange, Err: fmt.Errorf("%v < 1", limit)}
+	}
 
-func (d Driver) Paginate(c cursor.Cursor, input interface{}) (driver.Page, error) {
 	executor := d.ExecutorFactory(ExecutorFactoryArgs{
 		Input:  input,
 		Cursor: c,
+		Dst:    dst,
 	})
-	limit := c.Limit
-
-	if limit == 0 {
-		return nil, fmt.Errorf("limit is 0")
-	}
-
-	cvalue := c.Value
-	isFirst := cvalue == nil
-
-	if isFirst {
-		m, err := executor.TakeFirst()
-		if err != nil {
-			if errors.Is(err, ErrNoResult) {
-				return noResultPage{}, nil
-			}
 
-			return nil, err
+	if c.Until != nil {
+		ue, ok := executor.(UntilExecutor)
+		if !ok {
+			return nil, ErrUntilUnsupported
 		}
 
-		cvalue = m
+		executor = ue.Until(c.Until)
 	}
 
-	pc, err := executor.CountPrevious(cvalue)
-	if err != nil {
-		return nil, err
+	into := false
+	if ie, ok := executor.(IntoExecutor); ok && dst != nil {
+		into = ie.ScansInto()
 	}
 
-	nvalues, err := executor.FindNext(cvalue, isFirst)
+	nvalues, hasPreviousPage, err := d.find(ctx, executor, c.Value)
 	if err != nil {
 		return nil, err
 	}
 
 	nc := len(nvalues)
-	hasPreviousPage := pc > 0
 	hasNextPage := nc > limit
+	// The traversal of Before pages goes against the natural order
+	reverse := d.NaturalOrder && c.Type == cursor.Before
 
 	if nc == 0 {
-		return noResultPage{hasPreviousPage}, nil
+		p := noResultPage{hasPrevious: hasPreviousPage, executor: executor, natural: d.NaturalOrder, reverse: reverse}
+
+		// Empties dst, which may hold the rows of a previous call
+		if err := d.query(ctx, p, dst); err != nil {
+			return nil, err
+		}
+
+		return p, nil
 	}
 
 	mi := nc - 1
@@ -87,21 +139,26 @@ func (d Driver) Paginate(c cursor.Cursor, input interface{}) (driver.Page, error
 	sm := nvalues[si]
 	em := nvalues[ei]
 
-	sc, err := d.CursorEncode(sm)
+	encode := d.CursorEncode
+	if ce, ok := executor.(CursorEncodeExecutor); ok {
+		encode = ce.CursorEncode
+	}
+
+	sc, err := encode(sm)
 	if err != nil {
 		return nil, err
 	}
-	ec, err := d.CursorEncode(em)
+	ec, err := encode(em)
 	if err != nil {
 		return nil, err
 	}
 
-	pExecutor := executor.Page(sm, em)
-
-	return page{
-		Executor: pExecutor,
+	p := page{
+		Executor: executor.Page(sm, em),
+		executor: executor,
+		count:    int64(ei + 1),
 		cursorFunc: func(i int64) (interface{}, error) {
-			return d.CursorEncode(nvalues[i])
+			return encode(nvalues[i])
 		},
 		pageInfo: driver.PageInfo{
 			HasNextPage:     hasNextPage,
@@ -109,44 +166,178 @@ func (d Driver) Paginate(c cursor.Cursor, input interface{}) (driver.Page, error
 			StartCursor:     sc,
 			EndCursor:       ec,
 		},
-	}, nil
+		natural: d.NaturalOrder,
+	}
+
+	if reverse {
+		p.Executor = ReverseExecutor(p.Executor)
+		p.cursorFunc = func(i int64) (interface{}, error) {
+			return encode(nvalues[int64(ei)-i])
+		}
+		p.pageInfo = reversePageInfo(p.pageInfo)
+	}
+
+	if !into {
+		if err := d.query(ctx, p, dst); err != nil {
+			return nil, err
+		}
+
+		return p, nil
+	}
+
+	// The extra row only tells whether a next page exists
+	if err := Truncate(dst, ei+1); err != nil {
+		return nil, err
+	}
+
+	if reverse {
+		if err := reverseSlice(dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Queries the rows of p into dst, unless nil
+func (d Driver) query(ctx context.Context, p driver.Page, dst interface{}) error {
+	if dst == nil {
+		return nil
+	}
+
+	return p.Query(ctx, dst)
+}
+
+// Returns the values of the rows from cvalue (from the first row when nil) up to limit + 1,
+// and whether rows precede them
+func (d Driver) find(ctx context.Context, executor Executor, cvalue interface{}) ([]interface{}, bool, error) {
+	if pf, ok := executor.(PageFinder); ok {
+		return pf.FindPage(ctx, cvalue)
+	}
+
+	isFirst := cvalue == nil
+
+	if isFirst {
+		m, err := executor.TakeFirst(ctx)
+		if err != nil {
+			if errors.Is(err, ErrNoResult) {
+				return nil, false, nil
+			}
+
+			return nil, false, err
+		}
+
+		cvalue = m
+	}
+
+	// Nothing precedes the first row
+	var pc int64
+	if !isFirst {
+		var err error
+		pc, err = executor.CountPrevious(ctx, cvalue)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	nvalues, err := executor.FindNext(ctx, cvalue, isFirst)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return nvalues, pc > 0, nil
+}
+
+// Swaps the ends of info, to describe the page in the opposite order
+func reversePageInfo(info driver.PageInfo) driver.PageInfo {
+	return driver.PageInfo{
+		HasNextPage:     info.HasPreviousPage,
+		HasPreviousPage: info.HasNextPage,
+		StartCursor:     info.EndCursor,
+		EndCursor:       info.StartCursor,
+	}
 }
 
 type noResultPage struct {
 	hasPrevious bool
+	executor    Executor
+	// See Driver.NaturalOrder
+	natural bool
+	reverse bool
 }
 
-func (n noResultPage) Query(interface{}) error {
+func (n noResultPage) Query(_ context.Context, dst interface{}) error {
+	Empty(dst)
+
 	return nil
 }
 
-func (n noResultPage) Count() (int64, error) {
+func (n noResultPage) Count(context.Context) (int64, error) {
 	return 0, nil
 }
 
 func (n noResultPage) Cursor(int64) (interface{}, error) {
-	return nil, errors.New("no cursor available")
+	return nil, driver.ErrNoCursor
+}
+
+func (n noResultPage) TotalCount(ctx context.Context) (int64, error) {
+	return totalCount(ctx, n.executor)
 }
 
 func (n noResultPage) Info() driver.PageInfo {
-	return driver.PageInfo{
+	info := driver.PageInfo{
 		HasPreviousPage: n.hasPrevious,
 		HasNextPage:     false,
 		StartCursor:     nil,
 		EndCursor:       nil,
 	}
+
+	if n.reverse {
+		return reversePageInfo(info)
+	}
+
+	return info
+}
+
+func (n noResultPage) NaturalOrder() bool {
+	return n.natural
 }
 
 type page struct {
 	driver.Executor
+	executor Executor
+	// Number of rows of the page, the extra row fetched to find the next page excluded
+	count      int64
 	pageInfo   driver.PageInfo
 	cursorFunc func(i int64) (interface{}, error)
+	natural    bool
 }
 
 func (p page) Cursor(i int64) (interface{}, error) {
+	if i < 0 || i >= p.count {
+		return nil, fmt.Errorf("%w: %v", driver.ErrNoCursor, i)
+	}
+
 	return p.cursorFunc(i)
 }
 
 func (p page) Info() driver.PageInfo {
 	return p.pageInfo
 }
+
+func (p page) NaturalOrder() bool {
+	return p.natural
+}
+
+func (p page) TotalCount(ctx context.Context) (int64, error) {
+	return totalCount(ctx, p.executor)
+}
+
+func totalCount(ctx context.Context, e Executor) (int64, error) {
+	tc, ok := e.(driver.TotalCounter)
+	if !ok {
+		return 0, driver.ErrTotalCountUnsupported
+	}
+
+	return tc.TotalCount(ctx)
+}
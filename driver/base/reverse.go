@@ -0,0 +1,61 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"reflect"
+)
+
+// Wraps e so that the rows returned by Query are in the reverse order,
+// Query must be called with a pointer to a slice
+func ReverseExecutor(e driver.Executor) driver.Executor {
+	return reverseExecutor{e}
+}
+
+type reverseExecutor struct {
+	driver.Executor
+}
+
+func (r reverseExecutor) Query(ctx context.Context, dst interface{}) error {
+	if err := r.Executor.Query(ctx, dst); err != nil {
+		return err
+	}
+
+	return reverseSlice(dst)
+}
+
+func reverseSlice(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("reverse: expected pointer to slice, got %T", dst)
+	}
+
+	s := v.Elem()
+	swap := reflect.Swapper(s.Interface())
+	for i, j := 0, s.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+
+	return nil
+}
+
+// Empties the slice pointed by dst, which may hold the rows of a previous call, when a page has no row.
+// Other destinations are left as is, nothing is to be queried into them
+func Empty(dst interface{}) {
+	_ = Truncate(dst, 0)
+}
+
+// Truncates the slice pointed by dst to its first n elements
+func Truncate(dst interface{}, n int) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("truncate: expected pointer to slice, got %T", dst)
+	}
+
+	if s := v.Elem(); s.Len() > n {
+		s.Set(s.Slice(0, n))
+	}
+
+	return nil
+}
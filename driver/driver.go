@@ -1,6 +1,10 @@
 package driver
 
-import "github.com/raphaelvigee/go-paginate/cursor"
+import (
+	"context"
+	"errors"
+	"github.com/raphaelvigee/go-paginate/cursor"
+)
 
 // Allows to transform the driver cursor value data to a potential smaller
 // form for marshaling (ex: use an array instead of a map, since we know
@@ -20,12 +24,12 @@ type CursorEncoder interface {
 type Driver interface {
 	CursorEncoder
 
-	Paginate(c cursor.Cursor, input interface{}) (Page, error)
+	Paginate(ctx context.Context, c cursor.Cursor, input interface{}) (Page, error)
 }
 
 type Executor interface {
-	Query(dst interface{}) error
-	Count() (int64, error)
+	Query(ctx context.Context, dst interface{}) error
+	Count(ctx context.Context) (int64, error)
 }
 
 type PageInfo struct {
@@ -40,3 +44,24 @@ type Page interface {
 	Cursor(i int64) (interface{}, error)
 	Info() PageInfo
 }
+
+var ErrTotalCountUnsupported = errors.New("total count is not supported by the driver")
+
+// Can be implemented by a Page (or the executors of a driver) to count all the rows
+// matching the input, regardless of the cursor and limit
+type TotalCounter interface {
+	// Must return ErrTotalCountUnsupported if the count cannot be computed
+	TotalCount(ctx context.Context) (int64, error)
+}
+
+// Can be implemented by a Page, when NaturalOrder returns true the rows and the PageInfo of the page are
+// in the natural order of the driver, regardless of the cursor type (see base.Driver.NaturalOrder)
+type NaturalOrderer interface {
+	NaturalOrder() bool
+}
+
+// Can be implemented by a Driver to scan the rows of the page into dst (a pointer to a slice) while paginating,
+// instead of querying them again through Page.Query
+type IntoPaginator interface {
+	PaginateInto(ctx context.Context, c cursor.Cursor, input interface{}, dst interface{}) (Page, error)
+}
@@ -0,0 +1,49 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// The cursor could not be decoded (ex: malformed, or issued by another paginator), caused by the client
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// The values of the cursor do not match the columns of the driver, also an ErrInvalidCursor
+	ErrCursorColumnMismatch = fmt.Errorf("%w: column mismatch", ErrInvalidCursor)
+	// The limit of the cursor must be at least 1
+	ErrLimitOutOfRange = errors.New("limit out of range")
+	// The page has no row at the requested index
+	ErrNoCursor = errors.New("no cursor available")
+)
+
+// Error of a given Kind (ex: ErrInvalidCursor), wrapping its cause.
+// errors.Is matches both the Kind (and the errors it wraps) and the cause
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return errors.Is(e.Kind, target)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wraps err with ErrInvalidCursor, unless it already is one
+func InvalidCursor(err error) error {
+	if err == nil || errors.Is(err, ErrInvalidCursor) {
+		return err
+	}
+
+	return &Error{Kind: ErrInvalidCursor, Err: err}
+}
@@ -3,102 +3,197 @@ package gorm
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"github.com/raphaelvigee/go-paginate/driver"
 	"github.com/raphaelvigee/go-paginate/driver/base"
 	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"regexp"
+	"strings"
 )
 
 type Column = sqlbase.Column
 
 type Options struct {
 	Columns []Column
+	// See sqlbase.Options
+	RowValues bool
+	// See base.Driver
+	NaturalOrder bool
+	// See sqlbase.Options
+	SingleQuery bool
+	// Detected from the gorm.Dialector of the input when empty, see sqlbase.DialectFor
+	Dialect sqlbase.Dialect
+	// Overrides the primary key of the model of the input, appended to the columns when they are not
+	// guaranteed to be unique (see sqlbase.AppendPrimaryKey), mark a column as Unique to opt out
+	PrimaryKey []Column
 }
 
 func New(o Options) driver.Driver {
-	return sqlbase.New(sqlbase.Options{
-		Columns: o.Columns,
-		ExecutorFactory: func(args sqlbase.ExecutorFactoryArgs) sqlbase.Executor {
-			otx := fork(args.Input.(*gorm.DB))
-			orders := clause.Expr{}
-			selects := clause.Expr{}
-
-			columnWrapper := func(col string) string {
-				var buf bytes.Buffer
+	return sqlbase.New(baseOptions(o))
+}
 
-				if otx.Statement.Table != "" {
-					otx.Statement.DB.Dialector.QuoteTo(&buf, otx.Statement.Table)
-					buf.WriteByte('.')
-				}
-				otx.Statement.DB.Dialector.QuoteTo(&buf, col)
+// Same as New, validating the options first, see sqlbase.Options.Validate
+func NewValidated(o Options) (driver.Driver, error) {
+	return sqlbase.NewValidated(baseOptions(o))
+}
 
-				return buf.String()
-			}
+func baseOptions(o Options) sqlbase.Options {
+	return sqlbase.Options{
+		Columns:        o.Columns,
+		RowValues:      o.RowValues,
+		NaturalOrder:   o.NaturalOrder,
+		SingleQuery:    o.SingleQuery,
+		Dialect:        o.Dialect,
+		DialectFunc:    dialectFunc(o.Dialect),
+		PrimaryKeyFunc: primaryKeyFunc(o.PrimaryKey),
+		ExecutorFactory: func(args sqlbase.ExecutorFactoryArgs) sqlbase.Executor {
+			tx := fork(args.Input.(*gorm.DB))
+			otx := fork(tx)
 
-			for _, column := range o.Columns {
-				order := column.Order(args.Cursor.Type)
-
-				wc := Column{
-					Name:        columnWrapper(column.Name),
-					Desc:        column.Desc,
-					Reference:   column.Reference,
-					Placeholder: column.Placeholder,
-				}
-
-				col, vars := wc.Reference(wc)
-
-				// Order
-				if orders.SQL != "" {
-					orders.SQL += ","
-				}
-				orders.SQL += fmt.Sprintf("%v %v", col, order)
-				orders.Vars = append(orders.Vars, vars...)
-
-				// Select
-				if selects.SQL != "" {
-					selects.SQL += ","
-				}
-				selects.SQL += col
-				if column.Name != col {
-					selects.SQL += " AS " + column.Name
-				}
-				selects.Vars = append(selects.Vars, vars...)
-			}
+			columnWrapper := columnWrapper(otx)
 
+			orders, ordersVars := sqlbase.OrderBy(args.Columns, args.Cursor.Type, columnWrapper)
 			otx.Statement.AddClause(clause.OrderBy{
-				Expression: orders,
+				Expression: clause.Expr{SQL: orders, Vars: ordersVars},
 			})
 
+			selects, selectsVars := sqlbase.Select(args.Columns, columnWrapper)
 			stx := fork(otx)
 			stx.Statement.AddClause(clause.Select{
-				Expression: selects,
+				Expression: clause.Expr{SQL: selects, Vars: selectsVars},
 			})
 
+			aliased, aliasedVars := sqlbase.SelectAliased(args.Columns, columnWrapper)
+
 			return gormExecutor{
 				columnWrapper: columnWrapper,
+				tx:            tx,
 				otx:           otx,
 				stx:           stx,
+				selects:       selects,
+				selectsVars:   selectsVars,
+				aliased:       aliased,
+				aliasedVars:   aliasedVars,
 			}
 		},
-	})
+	}
+}
+
+func dialectFunc(dialect sqlbase.Dialect) func(input interface{}) sqlbase.Dialect {
+	return func(input interface{}) sqlbase.Dialect {
+		if dialect.Name != "" {
+			return dialect
+		}
+
+		d, _ := sqlbase.DialectFor(input.(*gorm.DB).Dialector.Name())
+
+		return d
+	}
+}
+
+func primaryKeyFunc(primaryKey []Column) func(input interface{}) []Column {
+	return func(input interface{}) []Column {
+		if len(primaryKey) > 0 {
+			return primaryKey
+		}
+
+		return modelPrimaryKey(input.(*gorm.DB))
+	}
+}
+
+// Returns the columns of the primary key of the model of tx, nil without model
+func modelPrimaryKey(tx *gorm.DB) []Column {
+	if tx.Statement.Model == nil {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx.Statement.DB}
+	if err := stmt.Parse(tx.Statement.Model); err != nil {
+		return nil
+	}
+
+	columns := make([]Column, len(stmt.Schema.PrimaryFields))
+	for i, field := range stmt.Schema.PrimaryFields {
+		columns[i] = Column{
+			Name: field.DBName,
+			Time: field.DataType == schema.Time,
+		}
+	}
+
+	return columns
+}
+
+// Quotes the column, qualified with the table of tx (see inputTable), unless already qualified
+// (ex: `orders.created_at` for a joined table, or `u.name` for an alias)
+func columnWrapper(tx *gorm.DB) func(col string) string {
+	table := inputTable(tx)
+
+	return func(col string) string {
+		var buf bytes.Buffer
+
+		if table != "" && !strings.Contains(col, ".") {
+			tx.Statement.DB.Dialector.QuoteTo(&buf, table)
+			buf.WriteByte('.')
+		}
+		tx.Statement.DB.Dialector.QuoteTo(&buf, col)
+
+		return buf.String()
+	}
+}
+
+// Matches the alias of a table expression, ex: `users u` or `users AS u`
+var tableAliasRegexp = regexp.MustCompile(`(?i)^\s*\S+\s+(?:AS\s+)?(\w+)\s*$`)
+
+// Returns the name the table of tx can be referenced by: its alias, its name, or the table of its model
+func inputTable(tx *gorm.DB) string {
+	if tx.Statement.TableExpr != nil {
+		if m := tableAliasRegexp.FindStringSubmatch(tx.Statement.TableExpr.SQL); m != nil {
+			return m[1]
+		}
+	}
+
+	if tx.Statement.Table != "" {
+		return tx.Statement.Table
+	}
+
+	if tx.Statement.Model != nil {
+		stmt := &gorm.Statement{DB: tx.Statement.DB}
+		if err := stmt.Parse(tx.Statement.Model); err == nil {
+			return stmt.Table
+		}
+	}
+
+	return ""
 }
 
 type gormExecutor struct {
+	// Input transaction
+	tx *gorm.DB
 	// Ordered transaction
 	otx *gorm.DB
 	// Ordered & selected transaction
 	stx           *gorm.DB
 	columnWrapper func(col string) string
+
+	selects     string
+	selectsVars []interface{}
+	// Columns selected along with the rows, see sqlbase.SelectAliased
+	aliased     string
+	aliasedVars []interface{}
 }
 
+var _ sqlbase.SingleQueryExecutor = (*gormExecutor)(nil)
+var _ sqlbase.IntoExecutor = (*gormExecutor)(nil)
+
 func (d gormExecutor) WrapColumn(col string) string {
 	return d.columnWrapper(col)
 }
 
-func (d gormExecutor) TakeFirst() (map[string]interface{}, error) {
-	m, err := TakeMap(fork(d.stx).Limit(1))
+func (d gormExecutor) TakeFirst(ctx context.Context) (map[string]interface{}, error) {
+	m, err := TakeMap(withContext(d.stx, ctx).Limit(1))
 	if err != nil {
 		return nil, err
 	}
@@ -110,13 +205,105 @@ func (d gormExecutor) TakeFirst() (map[string]interface{}, error) {
 	return m, nil
 }
 
-func (d gormExecutor) CountPrevious(where string, args []interface{}) (int64, error) {
+func (d gormExecutor) TotalCount(ctx context.Context) (int64, error) {
+	var c int64
+	return c, counting(withContext(d.otx, ctx)).Count(&c).Error
+}
+
+func (d gormExecutor) CountPrevious(ctx context.Context, where string, args []interface{}) (int64, error) {
 	var pc int64
-	return pc, fork(d.otx).Where(where, args...).Limit(1).Count(&pc).Error
+	return pc, counting(withContext(d.otx, ctx)).Where(where, args...).Limit(1).Count(&pc).Error
+}
+
+func (d gormExecutor) FindNext(ctx context.Context, query string, args []interface{}, limit int) ([]map[string]interface{}, error) {
+	return FindMap(withContext(d.stx, ctx).Where(query, args...).Limit(limit))
+}
+
+func (d gormExecutor) FindNextWithPrevious(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int) ([]map[string]interface{}, error) {
+	tx := d.stx
+	if previous != "" {
+		tx = d.selected(d.selects, d.selectsVars, previous, previousArgs)
+	}
+
+	return FindMap(d.next(tx, ctx, query, args, limit))
+}
+
+// dst must be a pointer to a slice of structs (or pointers to structs) or of map[string]interface{}, see FindMapInto
+func (d gormExecutor) CanScanInto(dst interface{}) bool {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return false
+	}
+
+	et := v.Elem().Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+
+	return et.Kind() == reflect.Struct || et == reflect.TypeOf(map[string]interface{}{})
+}
+
+func (d gormExecutor) FindNextInto(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int, dst interface{}) ([]map[string]interface{}, error) {
+	tx := d.selected(inputSelects(d.otx)+", "+d.aliased, d.aliasedVars, previous, previousArgs)
+
+	return FindMapInto(d.next(tx, ctx, query, args, limit), dst)
+}
+
+// Forks the ordered transaction with selects, along with whether any row matches previous (unless empty)
+// as a sqlbase.HasPreviousAlias column
+func (d gormExecutor) selected(selects string, vars []interface{}, previous string, previousArgs []interface{}) *gorm.DB {
+	vars = vars[:len(vars):len(vars)]
+
+	if previous != "" {
+		ptx := fork(d.tx).Where(previous, previousArgs...)
+		ptx.Statement.AddClause(clause.Select{
+			Expression: clause.Expr{SQL: "1"},
+		})
+
+		var buf bytes.Buffer
+		d.otx.Statement.DB.Dialector.QuoteTo(&buf, sqlbase.HasPreviousAlias)
+
+		selects += ", EXISTS (?) AS " + buf.String()
+		vars = append(vars, ptx)
+	}
+
+	tx := fork(d.otx)
+	tx.Statement.AddClause(clause.Select{
+		Expression: clause.Expr{SQL: selects, Vars: vars},
+	})
+
+	return tx
 }
 
-func (d gormExecutor) FindNext(query string, args []interface{}, limit int) ([]map[string]interface{}, error) {
-	return FindMap(fork(d.stx).Where(query, args...).Limit(limit))
+func (d gormExecutor) next(tx *gorm.DB, ctx context.Context, query string, args []interface{}, limit int) *gorm.DB {
+	tx = withContext(tx, ctx)
+	if query != "" {
+		tx = tx.Where(query, args...)
+	}
+
+	return tx.Limit(limit)
+}
+
+// Columns selected by the input (see gorm.DB.Select), all of them by default
+func inputSelects(tx *gorm.DB) string {
+	if len(tx.Statement.Selects) == 0 {
+		return "*"
+	}
+
+	var buf bytes.Buffer
+	for i, s := range tx.Statement.Selects {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if strings.ContainsAny(s, " (*") {
+			buf.WriteString(s)
+		} else {
+			tx.Statement.DB.Dialector.QuoteTo(&buf, s)
+		}
+	}
+
+	return buf.String()
 }
 
 func (d gormExecutor) Page(where string, args []interface{}, limit int) driver.Executor {
@@ -129,21 +316,21 @@ type pageExecutor struct {
 	tx *gorm.DB
 }
 
-func (p pageExecutor) Query(dst interface{}) error {
+func (p pageExecutor) Query(ctx context.Context, dst interface{}) error {
 	if p.tx == nil {
 		return nil
 	}
 
-	return fork(p.tx).Find(dst).Error
+	return withContext(p.tx, ctx).Find(dst).Error
 }
 
-func (p pageExecutor) Count() (int64, error) {
+func (p pageExecutor) Count(ctx context.Context) (int64, error) {
 	if p.tx == nil {
 		return 0, nil
 	}
 
 	var c int64
-	err := fork(p.tx).Count(&c).Error
+	err := counting(withContext(p.tx, ctx)).Count(&c).Error
 
 	return c, err
 }
@@ -155,3 +342,23 @@ func fork(tx *gorm.DB) *gorm.DB {
 	}
 	return tx.Session(&gorm.Session{Context: ctx})
 }
+
+// Drops the columns selected by the input from tx, which must be a fork: the rows are counted regardless of them
+// (gorm counts the single selected column instead, which fails for `table.*`), unless they are DISTINCT
+func counting(tx *gorm.DB) *gorm.DB {
+	if !tx.Statement.Distinct {
+		tx.Statement.Selects = nil
+	}
+
+	return tx
+}
+
+// Forks tx with ctx, unless ctx is context.Background(), in which case the context
+// carried by tx is kept (allows to keep using gormDB.WithContext(ctx) with Paginator.Paginate)
+func withContext(tx *gorm.DB, ctx context.Context) *gorm.DB {
+	if ctx == context.Background() {
+		return fork(tx)
+	}
+
+	return tx.Session(&gorm.Session{Context: ctx})
+}
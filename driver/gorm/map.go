@@ -1,8 +1,11 @@
 package gorm
 
 import (
+	"fmt"
 	"github.com/raphaelvigee/go-paginate/driver/sql"
+	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
 	"gorm.io/gorm"
+	"reflect"
 )
 
 func TakeMap(tx *gorm.DB) (map[string]interface{}, error) {
@@ -33,3 +36,63 @@ func FindMap(tx *gorm.DB) ([]map[string]interface{}, error) {
 
 	return sql.RowsMap(rows)
 }
+
+// Same as FindMap, but also scans the rows into dst, a pointer to a slice of structs (see gorm.DB.ScanRows)
+// or to a slice of map[string]interface{}, which do not carry the aliases of the driver (see sqlbase.IsAlias)
+func FindMapInto(tx *gorm.DB, dst interface{}) ([]map[string]interface{}, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("gorm: scan: expected pointer to slice, got %T", dst)
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s := v.Elem()
+	et := s.Type().Elem()
+	s.Set(reflect.MakeSlice(s.Type(), 0, 0))
+
+	r := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		m, err := sql.RowMap(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, m)
+
+		if et.Kind() == reflect.Map {
+			ev := reflect.MakeMap(et)
+			for k, v := range m {
+				if !sqlbase.IsAlias(k) {
+					ev.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(&v).Elem())
+				}
+			}
+
+			s.Set(reflect.Append(s, ev))
+			continue
+		}
+
+		var ev reflect.Value
+		if et.Kind() == reflect.Ptr {
+			ev = reflect.New(et.Elem())
+		} else {
+			ev = reflect.New(et)
+		}
+
+		if err := tx.ScanRows(rows, ev.Interface()); err != nil {
+			return nil, err
+		}
+
+		if et.Kind() != reflect.Ptr {
+			ev = ev.Elem()
+		}
+
+		s.Set(reflect.Append(s, ev))
+	}
+
+	return r, rows.Err()
+}
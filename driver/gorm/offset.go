@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"context"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/offset"
+	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Creates an OFFSET/LIMIT based driver (see offset.New), rows are ordered by the columns,
+// followed by the primary key of the model (see Options.PrimaryKey)
+func NewOffset(o Options) driver.Driver {
+	dialect := dialectFunc(o.Dialect)
+	primaryKey := primaryKeyFunc(o.PrimaryKey)
+
+	return offset.New(offset.Options{
+		ExecutorFactory: func(args offset.ExecutorFactoryArgs) offset.Executor {
+			otx := fork(args.Input.(*gorm.DB))
+			columns := sqlbase.AppendPrimaryKey(o.Columns, primaryKey(args.Input))
+			columns = sqlbase.ResolveColumnsDialect(columns, dialect(args.Input))
+
+			orders, ordersVars := sqlbase.OrderBy(columns, cursor.After, columnWrapper(otx))
+			otx.Statement.AddClause(clause.OrderBy{
+				Expression: clause.Expr{SQL: orders, Vars: ordersVars},
+			})
+
+			return offsetExecutor{otx: otx}
+		},
+		NaturalOrder: o.NaturalOrder,
+	})
+}
+
+type offsetExecutor struct {
+	// Ordered transaction
+	otx *gorm.DB
+}
+
+func (e offsetExecutor) Count(ctx context.Context) (int64, error) {
+	var c int64
+	return c, counting(withContext(e.otx, ctx)).Count(&c).Error
+}
+
+func (e offsetExecutor) Page(offset, limit int64) driver.Executor {
+	tx := fork(e.otx).Offset(int(offset)).Limit(int(limit))
+
+	return pageExecutor{tx: tx}
+}
@@ -0,0 +1,256 @@
+package gorm
+
+import (
+	"errors"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver/offset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func testOffsetPaginator(t *testing.T, typ cursor.Type, limit int, specs []spec) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: NewOffset(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	nextCursor := ""
+	for i, s := range specs {
+		t.Logf("Spec %v\n", i)
+		csr, err := pg.Cursor(nextCursor, typ, limit)
+		require.NoError(t, err)
+
+		res, err := pg.Paginate(csr, tx)
+		require.NoError(t, err)
+
+		assert.Equal(t, s.hasPreviousPage, res.PageInfo.HasPreviousPage)
+		assert.Equal(t, s.hasNextPage, res.PageInfo.HasNextPage)
+
+		sc, _ := res.Cursor(0)
+		assert.Equal(t, sc, res.PageInfo.StartCursor)
+		ec, _ := res.Cursor(int64(len(s.names) - 1))
+		assert.Equal(t, ec, res.PageInfo.EndCursor)
+
+		c, err := res.Count()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(s.names)), c)
+
+		tc, err := res.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), tc)
+
+		var users []User
+		err = res.Query(&users)
+		require.NoError(t, err)
+
+		require.Len(t, users, len(s.names))
+		for i, n := range s.names {
+			assert.Equal(t, n, users[i].Name)
+		}
+
+		nextCursor = res.PageInfo.EndCursor
+	}
+}
+
+func TestOffset_After(t *testing.T) {
+	testOffsetPaginator(t, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestOffset_Before(t *testing.T) {
+	testOffsetPaginator(t, cursor.Before, 3, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u3"},
+		},
+	})
+}
+
+func TestOffset_PageCursor(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: NewOffset(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	res, err := pg.Paginate(offset.PageCursor(1, 3), tx)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var users []User
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "u2", users[0].Name)
+
+	res, err = pg.Paginate(offset.PageCursor(5, 3), tx)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+	assert.Empty(t, res.PageInfo.StartCursor)
+	assert.Empty(t, res.PageInfo.EndCursor)
+
+	c, err := res.Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), c)
+}
+
+func TestOffset_Window(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: NewOffset(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	// Between the first and the last rows: u1, u4
+	csr := cursor.Cursor{Type: cursor.After, Limit: 3, Value: int64(0), Until: int64(3)}
+
+	res, err := pg.Paginate(csr, tx)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var users []User
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	require.Len(t, users, 2)
+	assert.Equal(t, "u1", users[0].Name)
+	assert.Equal(t, "u4", users[1].Name)
+
+	csr = cursor.Cursor{Type: cursor.Before, Limit: 1, Value: int64(3), Until: int64(0)}
+
+	res, err = pg.Paginate(csr, tx)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "u4", users[0].Name)
+}
+
+func TestOffset_Before_NaturalOrder(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: NewOffset(Options{
+			Columns:      simpleColumns,
+			NaturalOrder: true,
+		}),
+	})
+
+	res, err := pg.Paginate(cursor.Cursor{Type: cursor.Before, Limit: 3}, tx)
+	require.NoError(t, err)
+
+	assert.True(t, res.NaturalOrder)
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var users []User
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	require.Len(t, users, 3)
+	assert.Equal(t, "u1", users[0].Name)
+	assert.Equal(t, "u4", users[1].Name)
+	assert.Equal(t, "u2", users[2].Name)
+
+	csr, err := pg.Cursor(res.PageInfo.StartCursor, cursor.Before, 3)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(csr, tx)
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "u3", users[0].Name)
+}
+
+func TestOffset_OutOfRange(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: NewOffset(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	for _, typ := range []cursor.Type{cursor.After, cursor.Before} {
+		// Forged by a client
+		data, err := pg.CursorMarshaller.Marshal(int64(-5))
+		require.NoError(t, err)
+
+		_, err = pg.Cursor(string(data), typ, 2)
+		assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor), typ)
+
+		for _, value := range []int64{-5, math.MaxInt64} {
+			res, err := pg.Paginate(cursor.Cursor{Type: typ, Limit: 2, Value: value}, tx)
+			require.NoError(t, err)
+
+			c, err := res.Count()
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, c, int64(0), "%v %v", typ, value)
+			assert.LessOrEqual(t, c, int64(2), "%v %v", typ, value)
+
+			var users []User
+			err = res.Query(&users)
+			require.NoError(t, err)
+			assert.Len(t, users, int(c), "%v %v", typ, value)
+		}
+	}
+}
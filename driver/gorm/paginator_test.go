@@ -2,9 +2,12 @@ package gorm
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/raphaelvigee/go-paginate"
 	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
 	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -105,6 +108,10 @@ type spec struct {
 }
 
 func testPaginator(t *testing.T, columns []sqlbase.Column, typ cursor.Type, limit int, specs []spec) {
+	testPaginatorOptions(t, Options{Columns: columns}, typ, limit, specs)
+}
+
+func testPaginatorOptions(t *testing.T, o Options, typ cursor.Type, limit int, specs []spec) {
 	db, teardown := setup()
 	defer teardown()
 
@@ -113,9 +120,7 @@ func testPaginator(t *testing.T, columns []sqlbase.Column, typ cursor.Type, limi
 	printAll(tx)
 
 	pg := go_paginate.New(go_paginate.Options{
-		Driver: New(Options{
-			Columns: columns,
-		}),
+		Driver: New(o),
 	})
 
 	nextCursor := ""
@@ -139,6 +144,10 @@ func testPaginator(t *testing.T, columns []sqlbase.Column, typ cursor.Type, limi
 		require.NoError(t, err)
 		assert.Equal(t, int64(len(s.names)), c)
 
+		tc, err := res.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), tc)
+
 		var users []User
 		err = res.Query(&users)
 		require.NoError(t, err)
@@ -189,6 +198,10 @@ func TestFactory_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), c)
 
+	tc, err := res.TotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), tc)
+
 	var users []User
 	err = res.Query(&users)
 	require.NoError(t, err)
@@ -298,3 +311,764 @@ func TestFactory_Before_CompositeExpr(t *testing.T) {
 		},
 	})
 }
+
+var descColumns = []sqlbase.Column{
+	{
+		Name:        "created_at",
+		Desc:        true,
+		Placeholder: placeholderValue,
+		Reference:   columnName,
+	},
+	{
+		Name: "id",
+		Desc: true,
+	},
+}
+
+func TestFactory_After_RowValues(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: descColumns, RowValues: true}, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u1", "u3"},
+		},
+	})
+}
+
+func TestFactory_Before_RowValues(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: descColumns, RowValues: true}, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestFactory_Context(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.PaginateContext(context.Background(), csr, tx)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var users []User
+	err = res.QueryContext(ctx, &users)
+	assert.True(t, errors.Is(err, context.Canceled), err)
+
+	_, err = res.CountContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), err)
+
+	_, err = pg.PaginateContext(ctx, csr, tx)
+	assert.True(t, errors.Is(err, context.Canceled), err)
+}
+
+func TestFactory_CursorVersion(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	newPaginator := func(columns []sqlbase.Column) *go_paginate.Paginator {
+		return go_paginate.New(go_paginate.Options{
+			Driver: New(Options{
+				Columns: columns,
+			}),
+			CursorMarshaller: cursor.Chain(
+				cursor.Envelope(cursor.MsgPack(), cursor.EnvelopeOptions{
+					Version: sqlbase.ColumnsVersion(columns),
+				}),
+				cursor.Base64(base64.StdEncoding),
+			),
+		})
+	}
+
+	pg := newPaginator(compositeColumns)
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, tx)
+	require.NoError(t, err)
+
+	_, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	_, err = newPaginator(compositeColumnsExpr).Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	assert.True(t, errors.Is(err, cursor.ErrVersionMismatch))
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+}
+
+var codecColumns = []sqlbase.Column{
+	{
+		Name:  "created_at",
+		Codec: sqlbase.TimeCodec(),
+	},
+	{
+		Name:  "id",
+		Codec: sqlbase.StringCodec(),
+	},
+}
+
+func TestFactory_After_Codec(t *testing.T) {
+	testPaginator(t, codecColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestFactory_After_SingleQuery(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: compositeColumns, SingleQuery: true}, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u1", "u3"},
+		},
+	})
+}
+
+func TestFactory_Before_SingleQueryExpr(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: compositeColumnsExpr, SingleQuery: true}, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestFactory_SingleQuery_RoundTrips(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	queries := countQueries(t, db)
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns:     compositeColumns,
+			SingleQuery: true,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, db.Model(&User{}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, *queries)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	*queries = 0
+	res, err = pg.Paginate(csr, db.Model(&User{}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, *queries)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+}
+
+// Counts the statements executed on db, ignoring the dry runs building subqueries
+func countQueries(t *testing.T, db *gormdb.DB) *int {
+	queries := 0
+	count := func(tx *gormdb.DB) {
+		if !tx.DryRun {
+			queries++
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count", count))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count", count))
+
+	return &queries
+}
+
+func TestFactory_PaginateInto(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	queries := countQueries(t, db)
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 3)
+	require.NoError(t, err)
+
+	var users []User
+	res, err := pg.PaginateInto(csr, db.Model(&User{}), &users)
+	require.NoError(t, err)
+	// TakeFirst and FindNext
+	assert.Equal(t, 2, *queries)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, users, 3)
+	assert.Equal(t, "u3", users[0].Name)
+	assert.Equal(t, "u1", users[1].Name)
+	assert.Equal(t, "u4", users[2].Name)
+	assert.False(t, users[0].CreatedAt.IsZero())
+
+	var queried []User
+	require.NoError(t, res.Query(&queried))
+	assert.Equal(t, queried, users)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 3)
+	require.NoError(t, err)
+
+	*queries = 0
+	var maps []map[string]interface{}
+	res, err = pg.PaginateInto(csr, db.Model(&User{}), &maps)
+	require.NoError(t, err)
+	// CountPrevious and FindNext
+	assert.Equal(t, 2, *queries)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, maps, 1)
+	assert.Equal(t, "u2", maps[0]["name"])
+	for k := range maps[0] {
+		assert.False(t, sqlbase.IsAlias(k), k)
+	}
+}
+
+func TestFactory_PaginateInto_Before_NaturalOrder_SingleQuery(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	queries := countQueries(t, db)
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns:      simpleColumns,
+			NaturalOrder: true,
+			SingleQuery:  true,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.Before, 3)
+	require.NoError(t, err)
+
+	var users []*User
+	res, err := pg.PaginateInto(csr, db.Model(&User{}).Select("id", "name"), &users)
+	require.NoError(t, err)
+	assert.Equal(t, 1, *queries)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, users, 3)
+	assert.Equal(t, "u1", users[0].Name)
+	assert.Equal(t, "u4", users[1].Name)
+	assert.Equal(t, "u2", users[2].Name)
+	// Only the selected columns are scanned
+	assert.True(t, users[0].CreatedAt.IsZero())
+
+	sc, err := res.Cursor(0)
+	require.NoError(t, err)
+	assert.Equal(t, res.PageInfo.StartCursor, sc)
+
+	csr, err = pg.Cursor(res.PageInfo.StartCursor, cursor.Before, 3)
+	require.NoError(t, err)
+
+	*queries = 0
+	res, err = pg.PaginateInto(csr, db.Model(&User{}), &users)
+	require.NoError(t, err)
+	assert.Equal(t, 1, *queries)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "u3", users[0].Name)
+}
+
+func TestFactory_Typed(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	pg := go_paginate.For[User](go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	}))
+
+	csr, err := pg.Cursor("", cursor.Before, 3)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(context.Background(), csr, db.Model(&User{}))
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, res.Items, 3)
+	require.Len(t, res.Edges, 3)
+	for i, name := range []string{"u2", "u4", "u1"} {
+		assert.Equal(t, name, res.Items[i].Name)
+		assert.Equal(t, res.Items[i], res.Edges[i].Node)
+
+		ec, err := res.Cursor(int64(i))
+		require.NoError(t, err)
+		assert.Equal(t, ec, res.Edges[i].Cursor)
+	}
+	assert.Equal(t, res.PageInfo.EndCursor, res.Edges[2].Cursor)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.Before, 3)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(context.Background(), csr, db.Model(&User{}))
+	require.NoError(t, err)
+
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, "u3", res.Items[0].Name)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.Before, 3)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(context.Background(), csr, db.Model(&User{}))
+	require.NoError(t, err)
+
+	assert.Empty(t, res.Items)
+	assert.Empty(t, res.Edges)
+}
+
+func TestFactory_Dialect(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	// Same instant, stored with a different offset: only ordered correctly once normalized by `datetime()`
+	createdAt := time.Unix(0, 0).Add(4 * time.Hour).In(time.FixedZone("", 2*60*60))
+	require.NoError(t, db.Model(&User{}).Where("name = ?", "u1").Update("created_at", createdAt).Error)
+
+	// The dialect is detected from the gorm.Dialector
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: []sqlbase.Column{{Name: "created_at", Time: true}},
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+	for {
+		var users []User
+		res, err := pg.PaginateInto(csr, db.Model(&User{}), &users)
+		require.NoError(t, err)
+
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+
+		if !res.PageInfo.HasNextPage {
+			break
+		}
+
+		csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"u3", "u1", "u4", "u2"}, names)
+}
+
+func TestFactory_Dialect_Tags(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	columns, err := ColumnsFromModel(db, &struct {
+		Id        string    `paginate:"id,tiebreak"`
+		CreatedAt time.Time `paginate:"created_at,desc"`
+	}{})
+	require.NoError(t, err)
+	assert.True(t, columns[0].Time)
+	assert.False(t, columns[1].Time)
+
+	testPaginator(t, columns, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+type Order struct {
+	Id        int `gorm:"primarykey"`
+	UserId    string
+	CreatedAt time.Time
+}
+
+// Creates orders of the users, in the order of the names
+func setupOrders(t *testing.T, db *gormdb.DB, names ...string) {
+	require.NoError(t, db.AutoMigrate(&Order{}))
+	require.NoError(t, db.Where("1=1").Delete(&Order{}).Error)
+
+	for i, name := range names {
+		var user User
+		require.NoError(t, db.Where("name = ?", name).Take(&user).Error)
+		require.NoError(t, db.Create(&Order{Id: i + 1, UserId: user.Id, CreatedAt: user.CreatedAt}).Error)
+	}
+}
+
+func TestFactory_Joined(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	setupOrders(t, db, "u1", "u2", "u1", "u3")
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: []Column{
+				{Name: "u.name", Desc: true},
+				// Qualified with the table of the model, `created_at` exists in both tables
+				{Name: "created_at", Time: true},
+				{Name: "orders.id"},
+			},
+		}),
+	})
+
+	tx := db.Model(&Order{}).Select("orders.*").Joins("JOIN users u ON u.id = orders.user_id")
+
+	csr, err := pg.Cursor("", cursor.After, 3)
+	require.NoError(t, err)
+
+	var orders []Order
+	res, err := pg.PaginateInto(csr, tx, &orders)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasNextPage)
+	require.Len(t, orders, 3)
+	assert.Equal(t, 4, orders[0].Id)
+	assert.Equal(t, 2, orders[1].Id)
+	assert.Equal(t, 1, orders[2].Id)
+
+	var queried []Order
+	require.NoError(t, res.Query(&queried))
+	assert.Equal(t, orders, queried)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 3)
+	require.NoError(t, err)
+
+	res, err = pg.PaginateInto(csr, tx, &orders)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+	require.Len(t, orders, 1)
+	assert.Equal(t, 3, orders[0].Id)
+}
+
+func TestFactory_TableAlias(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	setupOrders(t, db, "u1", "u2")
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: []Column{
+				{Name: "name", Desc: true},
+			},
+		}),
+	})
+
+	for _, table := range []string{"users u", "users AS u"} {
+		t.Run(table, func(t *testing.T) {
+			// `name` is qualified with the alias, `id` would be ambiguous otherwise
+			tx := db.Table(table).Select("u.*").Joins("JOIN orders ON orders.user_id = u.id").Where("orders.id > ?", 0)
+
+			csr, err := pg.Cursor("", cursor.After, 1)
+			require.NoError(t, err)
+
+			var users []User
+			res, err := pg.PaginateInto(csr, tx, &users)
+			require.NoError(t, err)
+
+			assert.True(t, res.PageInfo.HasNextPage)
+			require.Len(t, users, 1)
+			assert.Equal(t, "u2", users[0].Name)
+
+			csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 1)
+			require.NoError(t, err)
+
+			res, err = pg.PaginateInto(csr, tx, &users)
+			require.NoError(t, err)
+
+			assert.False(t, res.PageInfo.HasNextPage)
+			require.Len(t, users, 1)
+			assert.Equal(t, "u1", users[0].Name)
+		})
+	}
+}
+
+// Paginates through all the rows one by one, in the direction of typ
+func paginateAll(t *testing.T, pg *go_paginate.Paginator, tx *gormdb.DB, typ cursor.Type) []string {
+	csr, err := pg.Cursor("", typ, 1)
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		var users []User
+		res, err := pg.PaginateInto(csr, tx, &users)
+		require.NoError(t, err)
+
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+
+		if !res.PageInfo.HasNextPage {
+			break
+		}
+
+		csr, err = pg.Cursor(res.PageInfo.EndCursor, typ, 1)
+		require.NoError(t, err)
+	}
+
+	return names
+}
+
+func TestFactory_PrimaryKeyTiebreak(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	// u1, u3 and u4 share the same time
+	createdAt := time.Unix(0, 0).UTC().Add(4 * time.Hour)
+	require.NoError(t, db.Model(&User{}).Where("name IN ?", []string{"u3", "u4"}).Update("created_at", createdAt).Error)
+
+	for _, typ := range []cursor.Type{cursor.After, cursor.Before} {
+		pg := go_paginate.New(go_paginate.Options{
+			Driver: New(Options{
+				Columns: []Column{{Name: "created_at", Time: true}},
+			}),
+		})
+
+		names := paginateAll(t, pg, db.Model(&User{}), typ)
+		assert.ElementsMatch(t, []string{"u1", "u2", "u3", "u4"}, names, typ)
+
+		// Without tiebreak, only one of the rows sharing the time is returned
+		pg = go_paginate.New(go_paginate.Options{
+			Driver: New(Options{
+				Columns: []Column{{Name: "created_at", Time: true, Unique: true}},
+			}),
+		})
+
+		names = paginateAll(t, pg, db.Model(&User{}), typ)
+		assert.Len(t, names, 2, typ)
+	}
+}
+
+func TestNewValidated(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	_, err := NewValidated(Options{})
+	assert.True(t, errors.Is(err, sqlbase.ErrNoColumns))
+
+	_, err = NewValidated(Options{
+		Columns: []Column{{Name: "name"}, {Name: "name", Desc: true}},
+	})
+	assert.EqualError(t, err, `sqlbase: invalid options: duplicate column "name"`)
+
+	d, err := NewValidated(Options{
+		Columns: []Column{{Name: "created_at", Time: true}},
+	})
+	require.NoError(t, err)
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: d,
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	var users []User
+	_, err = pg.PaginateInto(csr, db.Model(&User{}), &users)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+}
+
+func TestFactory_Errors(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	newPaginator := func(columns []Column) *go_paginate.Paginator {
+		return go_paginate.New(go_paginate.Options{
+			Driver: New(Options{Columns: columns}),
+		})
+	}
+
+	pg := newPaginator([]Column{{Name: "created_at", Time: true}})
+
+	_, err := pg.Cursor("not base64!", cursor.After, 2)
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+	var cerr base64.CorruptInputError
+	assert.True(t, errors.As(err, &cerr), "wraps the cause")
+
+	// A cursor of another paginator, holding a single value
+	other := newPaginator([]Column{{Name: "created_at", Time: true, Unique: true}})
+	csr, err := other.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+	res, err := other.Paginate(csr, db.Model(&User{}))
+	require.NoError(t, err)
+
+	_, err = newPaginator([]Column{{Name: "created_at", Time: true}, {Name: "name"}}).Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	assert.True(t, errors.Is(err, go_paginate.ErrCursorColumnMismatch))
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+
+	_, err = pg.WindowCursor("", "garbage", cursor.After, 2)
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+
+	csr, err = pg.Cursor("", cursor.After, 0)
+	require.NoError(t, err)
+	_, err = pg.Paginate(csr, db.Model(&User{}))
+	assert.True(t, errors.Is(err, go_paginate.ErrLimitOutOfRange))
+
+	csr, err = pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+	res, err = pg.Paginate(csr, db.Model(&User{}).Where("name = ?", "none"))
+	require.NoError(t, err)
+	_, err = res.Cursor(0)
+	assert.True(t, errors.Is(err, go_paginate.ErrNoCursor))
+
+	// Indexes outside of a page of 2 rows out of 4, the extra row included
+	for _, typ := range []cursor.Type{cursor.After, cursor.Before} {
+		csr, err = pg.Cursor("", typ, 2)
+		require.NoError(t, err)
+		res, err = pg.Paginate(csr, db.Model(&User{}))
+		require.NoError(t, err)
+
+		c, err := res.Cursor(1)
+		require.NoError(t, err)
+		assert.Equal(t, res.PageInfo.EndCursor, c)
+
+		for _, i := range []int64{-1, 2, 5} {
+			_, err = res.Cursor(i)
+			assert.True(t, errors.Is(err, go_paginate.ErrNoCursor), "%v %v", typ, i)
+		}
+	}
+}
+
+func TestFactory_PrimaryKeyTiebreak_Mismatch(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	tx := db.Model(&User{})
+
+	for _, singleQuery := range []bool{false, true} {
+		newPaginator := func(unique bool) *go_paginate.Paginator {
+			return go_paginate.New(go_paginate.Options{
+				Driver: New(Options{
+					Columns:     []Column{{Name: "created_at", Time: true, Unique: unique}},
+					SingleQuery: singleQuery,
+				}),
+			})
+		}
+
+		// Cursors without the primary key (ex: issued before it was appended), and the other way around
+		for _, unique := range []bool{false, true} {
+			issuer := newPaginator(unique)
+			csr, err := issuer.Cursor("", cursor.After, 2)
+			require.NoError(t, err)
+			res, err := issuer.Paginate(csr, tx)
+			require.NoError(t, err)
+
+			pg := newPaginator(!unique)
+			csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+			require.NoError(t, err)
+
+			_, err = pg.Paginate(csr, tx)
+			assert.True(t, errors.Is(err, go_paginate.ErrCursorColumnMismatch), "single query: %v, unique: %v", singleQuery, unique)
+
+			csr, err = pg.WindowCursor("", res.PageInfo.EndCursor, cursor.After, 2)
+			require.NoError(t, err)
+
+			_, err = pg.Paginate(csr, tx)
+			assert.True(t, errors.Is(err, go_paginate.ErrCursorColumnMismatch), "window, single query: %v, unique: %v", singleQuery, unique)
+		}
+	}
+}
+
+func TestFactory_PaginateInto_Empty_Reused(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	for _, d := range []driver.Driver{
+		New(Options{Columns: simpleColumns}),
+		NewOffset(Options{Columns: simpleColumns}),
+	} {
+		pg := go_paginate.New(go_paginate.Options{
+			Driver: d,
+		})
+
+		csr, err := pg.Cursor("", cursor.After, 2)
+		require.NoError(t, err)
+
+		var users []User
+		_, err = pg.PaginateInto(csr, db.Model(&User{}), &users)
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+
+		res, err := pg.PaginateInto(csr, db.Model(&User{}).Where("name = ?", "none"), &users)
+		require.NoError(t, err)
+		assert.False(t, res.PageInfo.HasNextPage)
+		assert.Len(t, users, 0, "%T", d)
+
+		// Nothing is queried into the other destinations
+		var user User
+		assert.NoError(t, res.Query(&user), "%T", d)
+		assert.NoError(t, res.Query(nil), "%T", d)
+	}
+}
@@ -0,0 +1,108 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strings"
+)
+
+const TagName = "paginate"
+
+var ErrNoTaggedColumn = errors.New("gorm: tag: no field tagged with `paginate`")
+
+// Builds the columns from the `paginate` tags of the fields of model, in the order of the fields, the tiebreak column last.
+// The tag is a comma separated list: the name of the column first (can be empty), followed by the options:
+//   - desc: orders the column in descending order
+//   - nullable: the column can be NULL, inferred for pointer fields
+//   - nullsfirst: orders the NULL values first, implies nullable
+//   - tiebreak: unique column ending the ordering, such as the primary key (see Column.Unique)
+//
+// ex: `paginate:"created_at,desc"`, `paginate:"id,tiebreak"`.
+// The names are validated against the columns of the model, as resolved by gorm (naming strategy, `column` tag),
+// and the time fields are flagged as Column.Time
+func ColumnsFromModel(db *gorm.DB, model interface{}) ([]Column, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0)
+	var tiebreak *Column
+	names := map[string]string{}
+
+	for _, field := range stmt.Schema.Fields {
+		tag, ok := field.Tag.Lookup(TagName)
+		if !ok {
+			continue
+		}
+
+		if field.DBName == "" || !field.Readable {
+			return nil, fmt.Errorf("gorm: tag: field %v is not a column", field.Name)
+		}
+
+		parts := strings.Split(tag, ",")
+
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			name = field.DBName
+		}
+
+		if name != field.DBName {
+			return nil, fmt.Errorf("gorm: tag: field %v: column %q does not match %q", field.Name, name, field.DBName)
+		}
+
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("gorm: tag: fields %v and %v reference the same column %q", other, field.Name, name)
+		}
+		names[name] = field.Name
+
+		column := Column{
+			Name:     name,
+			Nullable: field.FieldType.Kind() == reflect.Ptr,
+			Time:     field.DataType == schema.Time,
+		}
+
+		isTiebreak := false
+		for _, opt := range parts[1:] {
+			switch strings.ToLower(strings.TrimSpace(opt)) {
+			case "desc":
+				column.Desc = true
+			case "nullable":
+				column.Nullable = true
+			case "nullsfirst":
+				column.Nullable = true
+				column.NullsFirst = true
+			case "tiebreak":
+				isTiebreak = true
+				column.Unique = true
+			default:
+				return nil, fmt.Errorf("gorm: tag: field %v: unknown option %q", field.Name, opt)
+			}
+		}
+
+		if isTiebreak {
+			if tiebreak != nil {
+				return nil, fmt.Errorf("gorm: tag: field %v: only one tiebreak column is allowed, %q already is", field.Name, tiebreak.Name)
+			}
+
+			c := column
+			tiebreak = &c
+			continue
+		}
+
+		columns = append(columns, column)
+	}
+
+	if tiebreak != nil {
+		columns = append(columns, *tiebreak)
+	}
+
+	if len(columns) == 0 {
+		return nil, ErrNoTaggedColumn
+	}
+
+	return columns, nil
+}
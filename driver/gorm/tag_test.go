@@ -0,0 +1,121 @@
+package gorm
+
+import (
+	"errors"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type TaggedUser struct {
+	Id        string `gorm:"primarykey" paginate:"id,tiebreak"`
+	Name      string `paginate:"name,desc"`
+	Score     *int   `paginate:""`
+	CreatedAt time.Time
+}
+
+func TestColumnsFromModel(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	columns, err := ColumnsFromModel(db, &TaggedUser{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Column{
+		{Name: "name", Desc: true},
+		{Name: "score", Nullable: true},
+		{Name: "id", Unique: true},
+	}, columns)
+}
+
+func TestColumnsFromModel_Errors(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	type renamed struct {
+		CreatedAt time.Time `gorm:"column:created" paginate:"created_at"`
+	}
+
+	type ignored struct {
+		Id   string
+		Name string `gorm:"-" paginate:"name"`
+	}
+
+	type unknown struct {
+		Name string `paginate:"name,up"`
+	}
+
+	type tiebreaks struct {
+		Id   string `paginate:"id,tiebreak"`
+		Name string `paginate:"name,tiebreak"`
+	}
+
+	type duplicate struct {
+		Name  string `paginate:"name"`
+		Other string `gorm:"column:name" paginate:"name"`
+	}
+
+	type none struct {
+		Name string
+	}
+
+	tests := []struct {
+		name  string
+		model interface{}
+		err   string
+	}{
+		{name: "renamed", model: &renamed{}, err: `gorm: tag: field CreatedAt: column "created_at" does not match "created"`},
+		{name: "ignored", model: &ignored{}, err: "gorm: tag: field Name is not a column"},
+		{name: "unknown option", model: &unknown{}, err: `gorm: tag: field Name: unknown option "up"`},
+		{name: "tiebreaks", model: &tiebreaks{}, err: `gorm: tag: field Name: only one tiebreak column is allowed, "id" already is`},
+		{name: "duplicate", model: &duplicate{}, err: `gorm: tag: fields Name and Other reference the same column "name"`},
+		{name: "none", model: &none{}, err: ErrNoTaggedColumn.Error()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ColumnsFromModel(db, test.model)
+			require.Error(t, err)
+			assert.Equal(t, test.err, err.Error())
+		})
+	}
+
+	_, err := ColumnsFromModel(db, &none{})
+	assert.True(t, errors.Is(err, ErrNoTaggedColumn))
+}
+
+func TestColumnsFromModel_Paginate(t *testing.T) {
+	db, teardown := setup()
+	defer teardown()
+
+	columns, err := ColumnsFromModel(db, &User{})
+	assert.True(t, errors.Is(err, ErrNoTaggedColumn))
+
+	columns, err = ColumnsFromModel(db, &struct {
+		Id   string `paginate:"id,tiebreak"`
+		Name string `paginate:",desc"`
+	}{})
+	require.NoError(t, err)
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: columns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 3)
+	require.NoError(t, err)
+
+	var users []User
+	res, err := pg.PaginateInto(csr, db.Model(&User{}), &users)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasNextPage)
+	require.Len(t, users, 3)
+	assert.Equal(t, "u4", users[0].Name)
+	assert.Equal(t, "u3", users[1].Name)
+	assert.Equal(t, "u2", users[2].Name)
+}
@@ -0,0 +1,391 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
+	"go.mongodb.org/mongo-driver/bson"
+	gomongo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+)
+
+type Column struct {
+	// Name of the field, dot notation can be used to reference a field of an embedded document
+	Name string
+	Desc bool
+	// Set to true if the field may be null or missing, MongoDB sorts them before any other value
+	// (first in an ascending order, last in a descending one)
+	Nullable bool
+}
+
+func (c Column) Order(t cursor.Type) sqlbase.Order {
+	order := sqlbase.OrderAsc
+	if c.Desc {
+		order = sqlbase.OrderDesc
+	}
+
+	if t == cursor.Before {
+		return order.Invert()
+	}
+
+	return order
+}
+
+// Subset of *mongo.Cursor used by the driver
+type Cursor interface {
+	All(ctx context.Context, results interface{}) error
+}
+
+// Subset of *mongo.Collection used by the driver, see WrapCollection
+type Collection interface {
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+}
+
+// Adapts *mongo.Collection to Collection
+func WrapCollection(c *gomongo.Collection) Collection {
+	return collection{c}
+}
+
+type collection struct {
+	c *gomongo.Collection
+}
+
+func (c collection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error) {
+	return c.c.Find(ctx, filter, opts...)
+}
+
+func (c collection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return c.c.CountDocuments(ctx, filter, opts...)
+}
+
+type Input struct {
+	Collection Collection
+	// Filter of the documents to paginate, nil for all documents
+	Filter interface{}
+}
+
+type Options struct {
+	Columns []Column
+	// See base.Driver
+	NaturalOrder bool
+}
+
+var operators = map[sqlbase.Op]string{
+	sqlbase.OpGt:  "$gt",
+	sqlbase.OpGte: "$gte",
+	sqlbase.OpLt:  "$lt",
+	sqlbase.OpLte: "$lte",
+}
+
+// Driver paginating a MongoDB collection, Input must be passed to Paginate.
+// The cursor value is the BSON document of the values of the columns, which preserves their types
+func New(o Options) driver.Driver {
+	return base.Driver{
+		CursorEncoder: cursorEncoder{o.Columns},
+		ExecutorFactory: func(args base.ExecutorFactoryArgs) base.Executor {
+			return mongoExecutor{
+				ExecutorFactoryArgs: args,
+				input:               args.Input.(Input),
+				columns:             o.Columns,
+				pop:                 sqlbase.OpLt,
+				nop:                 sqlbase.OpGt,
+			}
+		},
+		NaturalOrder: o.NaturalOrder,
+	}
+}
+
+type cursorEncoder struct {
+	columns []Column
+}
+
+func (cursorEncoder) CursorEncode(input interface{}) (interface{}, error) {
+	return bson.Marshal(input)
+}
+
+func (d cursorEncoder) CursorDecode(input interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	data, ok := input.([]byte)
+	if !ok {
+		return nil, driver.InvalidCursor(errors.New("mongo: cursor: decode: only []byte are handled"))
+	}
+
+	values := bson.M{}
+	if err := bson.Unmarshal(data, &values); err != nil {
+		return nil, driver.InvalidCursor(err)
+	}
+
+	// A missing column would be compared to null, see find for the values of the missing fields
+	for _, column := range d.columns {
+		if _, ok := values[column.Name]; !ok {
+			return nil, &driver.Error{
+				Kind: driver.ErrCursorColumnMismatch,
+				Err:  fmt.Errorf("mongo: cursor: decode: missing column %q", column.Name),
+			}
+		}
+	}
+
+	if len(values) != len(d.columns) {
+		return nil, &driver.Error{
+			Kind: driver.ErrCursorColumnMismatch,
+			Err:  fmt.Errorf("mongo: cursor: decode: %v values for %v columns", len(values), len(d.columns)),
+		}
+	}
+
+	return values, nil
+}
+
+type mongoExecutor struct {
+	base.ExecutorFactoryArgs
+	input   Input
+	columns []Column
+	// See cursor.Cursor.Until
+	until bson.M
+
+	pop sqlbase.Op
+	nop sqlbase.Op
+}
+
+var _ base.Executor = (*mongoExecutor)(nil)
+var _ driver.TotalCounter = (*mongoExecutor)(nil)
+var _ base.UntilExecutor = (*mongoExecutor)(nil)
+
+func (e mongoExecutor) Until(until interface{}) base.Executor {
+	e.until = until.(bson.M)
+
+	return e
+}
+
+func (e mongoExecutor) TakeFirst(ctx context.Context) (interface{}, error) {
+	values, err := e.find(ctx, e.filter(e.untilFilter()...), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, base.ErrNoResult
+	}
+
+	return values[0], nil
+}
+
+func (e mongoExecutor) CountPrevious(ctx context.Context, cvalue interface{}) (int64, error) {
+	return e.input.Collection.CountDocuments(ctx, e.filter(e.GenerateFilter(e.Cursor.Type, cvalue.(bson.M), e.pop.Inclusive())))
+}
+
+func (e mongoExecutor) FindNext(ctx context.Context, cvalue interface{}, isFirst bool) ([]interface{}, error) {
+	if isFirst {
+		e.nop = e.nop.Inclusive()
+	}
+
+	filters := append([]bson.D{e.GenerateFilter(e.Cursor.Type, cvalue.(bson.M), e.nop)}, e.untilFilter()...)
+
+	values, err := e.find(ctx, e.filter(filters...), e.Cursor.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := make([]interface{}, len(values))
+	for i := range values {
+		arr[i] = values[i]
+	}
+
+	return arr, nil
+}
+
+func (e mongoExecutor) Page(sm, em interface{}) driver.Executor {
+	return pageExecutor{
+		collection: e.input.Collection,
+		filter: e.filter(
+			e.GenerateFilter(e.Cursor.Type, sm.(bson.M), e.nop.Inclusive()),
+			e.GenerateFilter(e.Cursor.Type, em.(bson.M), e.pop.Inclusive()),
+		),
+		opts: options.Find().SetSort(e.sort()).SetLimit(int64(e.Cursor.Limit)),
+	}
+}
+
+func (e mongoExecutor) TotalCount(ctx context.Context) (int64, error) {
+	return e.input.Collection.CountDocuments(ctx, e.filter())
+}
+
+func (e mongoExecutor) untilFilter() []bson.D {
+	if e.until == nil {
+		return nil
+	}
+
+	return []bson.D{e.GenerateFilter(e.Cursor.Type, e.until, e.pop)}
+}
+
+// Finds the values of the columns of the first limit documents matching filter
+func (e mongoExecutor) find(ctx context.Context, filter interface{}, limit int) ([]bson.M, error) {
+	projection := bson.D{}
+	for _, column := range e.columns {
+		projection = append(projection, bson.E{Key: column.Name, Value: 1})
+	}
+
+	c, err := e.input.Collection.Find(ctx, filter, options.Find().
+		SetSort(e.sort()).
+		SetLimit(int64(limit)).
+		SetProjection(projection),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []bson.M
+	if err := c.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	values := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		values[i] = bson.M{}
+		for _, column := range e.columns {
+			values[i][column.Name] = lookup(doc, column.Name)
+		}
+	}
+
+	return values, nil
+}
+
+func (e mongoExecutor) sort() bson.D {
+	sort := bson.D{}
+	for _, column := range e.columns {
+		direction := 1
+		if column.Order(e.Cursor.Type) == sqlbase.OrderDesc {
+			direction = -1
+		}
+
+		sort = append(sort, bson.E{Key: column.Name, Value: direction})
+	}
+
+	return sort
+}
+
+// Combines the filter of the input with the filters
+func (e mongoExecutor) filter(filters ...bson.D) bson.D {
+	a := bson.A{}
+	if e.input.Filter != nil {
+		a = append(a, e.input.Filter)
+	}
+
+	for _, f := range filters {
+		a = append(a, f)
+	}
+
+	if len(a) == 0 {
+		return bson.D{}
+	}
+
+	return bson.D{{Key: "$and", Value: a}}
+}
+
+// Mirrors sqlbase's GenerateCondition:
+// {$and: [{col: {$op=: v}}, {$or: [{col: {$op: v}}, previous]}]}
+func (e mongoExecutor) GenerateFilter(typ cursor.Type, values bson.M, op sqlbase.Op) bson.D {
+	var f bson.D
+	for i := len(e.columns) - 1; i >= 0; i-- {
+		column := e.columns[i]
+
+		cop := op
+		if column.Order(typ) == sqlbase.OrderDesc {
+			cop = cop.Opposite()
+		}
+
+		v := values[column.Name]
+
+		// Only the last column carries the inclusiveness of op
+		if i == len(e.columns)-1 {
+			f = compare(column, cop, v)
+			continue
+		}
+
+		f = bson.D{{Key: "$and", Value: bson.A{
+			compare(column, cop.Inclusive(), v),
+			bson.D{{Key: "$or", Value: bson.A{
+				compare(column, cop.Exclusive(), v),
+				f,
+			}}},
+		}}}
+	}
+
+	return f
+}
+
+// Mirrors sqlbase's Column.compare: the comparison operators do not match null or missing fields,
+// which are matched by {field: null} when the column is Nullable
+func compare(column Column, op sqlbase.Op, v interface{}) bson.D {
+	if !column.Nullable {
+		return bson.D{{Key: column.Name, Value: bson.D{{Key: operators[op], Value: v}}}}
+	}
+
+	// Nulls being the smallest values, op is looking at values towards them
+	towardsNulls := op == sqlbase.OpLt || op == sqlbase.OpLte
+
+	if v == nil {
+		switch {
+		case towardsNulls && op.IsInclusive():
+			return bson.D{{Key: column.Name, Value: nil}}
+		case towardsNulls:
+			return bson.D{{Key: column.Name, Value: bson.D{{Key: "$in", Value: bson.A{}}}}}
+		case op.IsInclusive():
+			return bson.D{}
+		default:
+			return bson.D{{Key: column.Name, Value: bson.D{{Key: "$ne", Value: nil}}}}
+		}
+	}
+
+	f := bson.D{{Key: column.Name, Value: bson.D{{Key: operators[op], Value: v}}}}
+	if towardsNulls {
+		return bson.D{{Key: "$or", Value: bson.A{f, bson.D{{Key: column.Name, Value: nil}}}}}
+	}
+
+	return f
+}
+
+// Returns the value of the field at the dot notation path name, nil if missing
+func lookup(doc interface{}, name string) interface{} {
+	for _, key := range strings.Split(name, ".") {
+		switch d := doc.(type) {
+		case bson.M:
+			doc = d[key]
+		case map[string]interface{}:
+			doc = d[key]
+		case bson.D:
+			doc = d.Map()[key]
+		default:
+			return nil
+		}
+	}
+
+	return doc
+}
+
+type pageExecutor struct {
+	collection Collection
+	filter     bson.D
+	opts       *options.FindOptions
+}
+
+// dst must be a pointer to a slice, see mongo.Cursor.All
+func (p pageExecutor) Query(ctx context.Context, dst interface{}) error {
+	c, err := p.collection.Find(ctx, p.filter, p.opts)
+	if err != nil {
+		return err
+	}
+
+	return c.All(ctx, dst)
+}
+
+func (p pageExecutor) Count(ctx context.Context) (int64, error) {
+	return p.collection.CountDocuments(ctx, p.filter)
+}
@@ -0,0 +1,452 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type Profile struct {
+	Score int `bson:"score"`
+}
+
+type User struct {
+	ID        int     `bson:"_id"`
+	Name      string  `bson:"name"`
+	CreatedAt int     `bson:"created_at"`
+	Profile   Profile `bson:"profile"`
+	Deleted   bool    `bson:"deleted"`
+}
+
+// In-process Collection, supports the subset of the query language used by the driver
+type mockCollection struct {
+	docs []bson.M
+}
+
+func newMockCollection(t *testing.T, docs ...interface{}) *mockCollection {
+	c := &mockCollection{}
+	for _, doc := range docs {
+		data, err := bson.Marshal(doc)
+		require.NoError(t, err)
+
+		m := bson.M{}
+		require.NoError(t, bson.Unmarshal(data, &m))
+
+		c.docs = append(c.docs, m)
+	}
+
+	return c
+}
+
+func (c *mockCollection) match(filter interface{}) []bson.M {
+	docs := make([]bson.M, 0)
+	for _, doc := range c.docs {
+		if matches(doc, filter) {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
+
+func (c *mockCollection) Find(_ context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error) {
+	docs := c.match(filter)
+	o := options.MergeFindOptions(opts...)
+
+	if o.Sort != nil {
+		sort.SliceStable(docs, func(i, j int) bool {
+			for _, e := range o.Sort.(bson.D) {
+				c := compareValues(lookup(docs[i], e.Key), lookup(docs[j], e.Key)) * e.Value.(int)
+				if c != 0 {
+					return c < 0
+				}
+			}
+
+			return false
+		})
+	}
+
+	if o.Limit != nil && int64(len(docs)) > *o.Limit {
+		docs = docs[:*o.Limit]
+	}
+
+	return mockCursor{docs}, nil
+}
+
+func (c *mockCollection) CountDocuments(_ context.Context, filter interface{}, _ ...*options.CountOptions) (int64, error) {
+	return int64(len(c.match(filter))), nil
+}
+
+type mockCursor struct {
+	docs []bson.M
+}
+
+func (c mockCursor) All(_ context.Context, results interface{}) error {
+	s := reflect.ValueOf(results).Elem()
+	s.Set(reflect.MakeSlice(s.Type(), 0, len(c.docs)))
+
+	for _, doc := range c.docs {
+		data, err := bson.Marshal(doc)
+		if err != nil {
+			return err
+		}
+
+		v := reflect.New(s.Type().Elem())
+		if err := bson.Unmarshal(data, v.Interface()); err != nil {
+			return err
+		}
+
+		s.Set(reflect.Append(s, v.Elem()))
+	}
+
+	return nil
+}
+
+func matches(doc bson.M, filter interface{}) bool {
+	for _, e := range filter.(bson.D) {
+		switch e.Key {
+		case "$and":
+			for _, f := range e.Value.(bson.A) {
+				if !matches(doc, f) {
+					return false
+				}
+			}
+		case "$or":
+			ok := false
+			for _, f := range e.Value.(bson.A) {
+				ok = ok || matches(doc, f)
+			}
+			if !ok {
+				return false
+			}
+		default:
+			v := lookup(doc, e.Key)
+
+			cond, ok := e.Value.(bson.D)
+			if !ok {
+				if compareValues(v, e.Value) != 0 {
+					return false
+				}
+				continue
+			}
+
+			for _, op := range cond {
+				var ok bool
+				switch op.Key {
+				case "$in":
+					for _, in := range op.Value.(bson.A) {
+						ok = ok || compareValues(v, in) == 0
+					}
+				case "$ne":
+					ok = compareValues(v, op.Value) != 0
+				case "$gt", "$gte", "$lt", "$lte":
+					// Null and missing fields are only compared to null, and the other values to a value
+					if (v == nil) != (op.Value == nil) {
+						return false
+					}
+
+					c := compareValues(v, op.Value)
+					ok = map[string]bool{"$gt": c > 0, "$gte": c >= 0, "$lt": c < 0, "$lte": c <= 0}[op.Key]
+				default:
+					panic("unsupported operator " + op.Key)
+				}
+
+				if !ok {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// Null (and missing fields) being the smallest value
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch a := a.(type) {
+	case int32, int64:
+		ai, bi := reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int()
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	case string:
+		switch {
+		case a < b.(string):
+			return -1
+		case a > b.(string):
+			return 1
+		}
+		return 0
+	case bool:
+		if a == b.(bool) {
+			return 0
+		}
+		if !a {
+			return -1
+		}
+		return 1
+	}
+
+	panic(fmt.Sprintf("unsupported value %T", a))
+}
+
+var users = []interface{}{
+	User{ID: 1, Name: "u1", CreatedAt: 4, Profile: Profile{Score: 3}},
+	User{ID: 2, Name: "u2", CreatedAt: 10, Profile: Profile{Score: 2}},
+	User{ID: 3, Name: "u3", CreatedAt: 1, Profile: Profile{Score: 3}},
+	User{ID: 4, Name: "u4", CreatedAt: 6, Profile: Profile{Score: 2}},
+	User{ID: 5, Name: "u5", CreatedAt: 2, Profile: Profile{Score: 1}, Deleted: true},
+}
+
+var simpleColumns = []Column{
+	{
+		Name: "created_at",
+	},
+}
+
+var compositeColumns = []Column{
+	{
+		Name: "profile.score",
+		Desc: true,
+	},
+	{
+		Name: "_id",
+	},
+}
+
+type spec struct {
+	hasPreviousPage bool
+	hasNextPage     bool
+	names           []string
+}
+
+func testPaginator(t *testing.T, columns []Column, typ cursor.Type, limit int, specs []spec) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: columns,
+		}),
+	})
+
+	input := Input{
+		Collection: newMockCollection(t, users...),
+		Filter:     bson.D{{Key: "deleted", Value: false}},
+	}
+
+	nextCursor := ""
+	for i, s := range specs {
+		t.Logf("Spec %v\n", i)
+		csr, err := pg.Cursor(nextCursor, typ, limit)
+		require.NoError(t, err)
+
+		res, err := pg.Paginate(csr, input)
+		require.NoError(t, err)
+
+		assert.Equal(t, s.hasPreviousPage, res.PageInfo.HasPreviousPage)
+		assert.Equal(t, s.hasNextPage, res.PageInfo.HasNextPage)
+
+		c, err := res.Count()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(s.names)), c)
+
+		tc, err := res.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), tc)
+
+		var page []User
+		err = res.Query(&page)
+		require.NoError(t, err)
+
+		require.Len(t, page, len(s.names))
+		for i, n := range s.names {
+			assert.Equal(t, n, page[i].Name)
+		}
+
+		nextCursor = res.PageInfo.EndCursor
+	}
+}
+
+func TestPaginator_After(t *testing.T) {
+	testPaginator(t, simpleColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestPaginator_Before(t *testing.T) {
+	testPaginator(t, simpleColumns, cursor.Before, 3, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u3"},
+		},
+	})
+}
+
+func TestPaginator_Composite(t *testing.T) {
+	testPaginator(t, compositeColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u1", "u3"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2", "u4"},
+		},
+	})
+}
+
+func TestPaginator_CompositeBefore(t *testing.T) {
+	testPaginator(t, compositeColumns, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u4", "u2"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u3", "u1"},
+		},
+	})
+}
+
+func TestGenerateFilter(t *testing.T) {
+	e := mongoExecutor{columns: compositeColumns}
+
+	f := e.GenerateFilter(cursor.After, bson.M{"profile.score": 3, "_id": 1}, "<")
+
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "profile.score", Value: bson.D{{Key: "$gte", Value: 3}}}},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "profile.score", Value: bson.D{{Key: "$gt", Value: 3}}}},
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$lt", Value: 1}}}},
+		}}},
+	}}}, f)
+}
+
+func TestCursorDecode_Mismatch(t *testing.T) {
+	e := cursorEncoder{compositeColumns}
+
+	encode := func(v bson.M) interface{} {
+		data, err := cursorEncoder{}.CursorEncode(v)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	// Issued for other columns
+	_, err := e.CursorDecode(encode(bson.M{"created_at": 1}))
+	assert.True(t, errors.Is(err, go_paginate.ErrCursorColumnMismatch))
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+
+	values := bson.M{}
+	for _, column := range compositeColumns {
+		values[column.Name] = nil
+	}
+
+	decoded, err := e.CursorDecode(encode(values))
+	require.NoError(t, err, "null values are carried")
+	assert.Equal(t, values, decoded)
+
+	values["created_at"] = 1
+	_, err = e.CursorDecode(encode(values))
+	assert.True(t, errors.Is(err, go_paginate.ErrCursorColumnMismatch), "extra value")
+}
+
+func TestPaginator_Nullable(t *testing.T) {
+	// The documents without published_at are sorted with the null ones, before any other value
+	docs := []interface{}{
+		bson.M{"_id": 1, "name": "u1", "published_at": 3},
+		bson.M{"_id": 2, "name": "u2"},
+		bson.M{"_id": 3, "name": "u3", "published_at": nil},
+		bson.M{"_id": 4, "name": "u4", "published_at": 1},
+		bson.M{"_id": 5, "name": "u5"},
+		bson.M{"_id": 6, "name": "u6", "published_at": 3},
+	}
+
+	tests := []struct {
+		desc  bool
+		typ   cursor.Type
+		names []string
+	}{
+		{false, cursor.After, []string{"u2", "u3", "u5", "u4", "u1", "u6"}},
+		{false, cursor.Before, []string{"u6", "u1", "u4", "u5", "u3", "u2"}},
+		{true, cursor.After, []string{"u1", "u6", "u4", "u2", "u3", "u5"}},
+		{true, cursor.Before, []string{"u5", "u3", "u2", "u4", "u6", "u1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("desc %v %v", test.desc, test.typ), func(t *testing.T) {
+			pg := go_paginate.New(go_paginate.Options{
+				Driver: New(Options{
+					Columns: []Column{
+						{Name: "published_at", Desc: test.desc, Nullable: true},
+						{Name: "_id"},
+					},
+				}),
+			})
+
+			input := Input{Collection: newMockCollection(t, docs...)}
+
+			var names []string
+			nextCursor := ""
+			for i := 0; i < len(docs); i++ {
+				csr, err := pg.Cursor(nextCursor, test.typ, 2)
+				require.NoError(t, err)
+
+				res, err := pg.Paginate(csr, input)
+				require.NoError(t, err)
+
+				var page []User
+				require.NoError(t, res.Query(&page))
+				for _, u := range page {
+					names = append(names, u.Name)
+				}
+
+				if !res.PageInfo.HasNextPage {
+					break
+				}
+				nextCursor = res.PageInfo.EndCursor
+			}
+
+			assert.Equal(t, test.names, names)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package offset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"math"
+	"reflect"
+)
+
+type ExecutorFactoryArgs struct {
+	base.ExecutorFactoryArgs
+}
+
+type Executor interface {
+	// Counts all the rows of the input
+	Count(ctx context.Context) (int64, error)
+	// Returns the rows in [offset, offset+limit), in the order defined by the driver (regardless of the cursor type)
+	Page(offset, limit int64) driver.Executor
+}
+
+type Options struct {
+	ExecutorFactory func(args ExecutorFactoryArgs) Executor
+	// See base.Driver
+	NaturalOrder bool
+}
+
+// Driver paginating through OFFSET/LIMIT, the cursor value is the offset of the row.
+// Allows jumping to any page (see PageCursor) and sorting on non unique columns,
+// at the cost of performance on large inputs, and stability when the data changes
+func New(o Options) driver.Driver {
+	return offsetDriver{o}
+}
+
+// Cursor pointing to the n-th page (starting from 0) of limit rows
+func PageCursor(n, limit int) cursor.Cursor {
+	c := cursor.Cursor{
+		Type:  cursor.After,
+		Limit: limit,
+	}
+
+	if n > 0 {
+		c.Value = int64(n*limit - 1)
+	}
+
+	return c
+}
+
+type offsetDriver struct {
+	o Options
+}
+
+func (d offsetDriver) CursorEncode(input interface{}) (interface{}, error) {
+	return input, nil
+}
+
+func (d offsetDriver) CursorDecode(input interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	var offset int64
+	v := reflect.ValueOf(input)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		offset = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return nil, driver.InvalidCursor(fmt.Errorf("offset: cursor: decode: %v is out of range", v.Uint()))
+		}
+		offset = int64(v.Uint())
+	default:
+		return nil, driver.InvalidCursor(errors.New("offset: cursor: decode: only integers are handled"))
+	}
+
+	if offset < 0 {
+		return nil, driver.InvalidCursor(fmt.Errorf("offset: cursor: decode: %v is negative", offset))
+	}
+
+	return offset, nil
+}
+
+func (d offsetDriver) Paginate(ctx context.Context, c cursor.Cursor, input interface{}) (driver.Page, error) {
+	executor := d.o.ExecutorFactory(ExecutorFactoryArgs{base.ExecutorFactoryArgs{
+		Input:  input,
+		Cursor: c,
+	}})
+	limit := int64(c.Limit)
+
+	if limit < 1 {
+		return nil, &driver.Error{Kind: driver.ErrLimitOutOfRange, Err: fmt.Errorf("%v < 1", limit)}
+	}
+
+	total, err := executor.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows of the page are [start, end), within the window [lower, upper) delimited by c.Until
+	var start, end int64
+	lower, upper := int64(0), total
+	if c.Type == cursor.Before {
+		end = total
+		if c.Value != nil {
+			end = min(c.Value.(int64), total)
+		}
+		if c.Until != nil {
+			lower = min(c.Until.(int64)+1, end)
+		}
+		start = max(end-limit, lower)
+	} else {
+		if c.Value != nil {
+			start = min(c.Value.(int64)+1, total)
+		}
+		if c.Until != nil {
+			upper = max(min(c.Until.(int64), total), start)
+		}
+		end = min(start+limit, upper)
+	}
+
+	// The cursors are decoded from the client, keep the page within the rows whatever their values
+	start = clamp(start, 0, total)
+	end = clamp(end, start, total)
+
+	p := page{
+		executor: executor.Page(start, end-start),
+		total:    total,
+		count:    end - start,
+		pageInfo: driver.PageInfo{
+			HasPreviousPage: start > lower,
+			HasNextPage:     end < upper,
+		},
+		cursorFunc: func(i int64) (interface{}, error) {
+			return start + i, nil
+		},
+		natural: d.o.NaturalOrder,
+	}
+
+	// Keep the same semantic as keyset pagination, the rows are in the order of the traversal
+	if c.Type == cursor.Before && !d.o.NaturalOrder {
+		p.executor = base.ReverseExecutor(p.executor)
+		p.pageInfo.HasPreviousPage, p.pageInfo.HasNextPage = p.pageInfo.HasNextPage, p.pageInfo.HasPreviousPage
+		p.cursorFunc = func(i int64) (interface{}, error) {
+			return end - 1 - i, nil
+		}
+	}
+
+	if p.count > 0 {
+		p.pageInfo.StartCursor, _ = p.cursorFunc(0)
+		p.pageInfo.EndCursor, _ = p.cursorFunc(p.count - 1)
+	}
+
+	return p, nil
+}
+
+type page struct {
+	executor   driver.Executor
+	total      int64
+	count      int64
+	pageInfo   driver.PageInfo
+	cursorFunc func(i int64) (interface{}, error)
+	natural    bool
+}
+
+func (p page) Query(ctx context.Context, dst interface{}) error {
+	if p.count == 0 {
+		base.Empty(dst)
+
+		return nil
+	}
+
+	return p.executor.Query(ctx, dst)
+}
+
+func (p page) Count(context.Context) (int64, error) {
+	return p.count, nil
+}
+
+func (p page) TotalCount(context.Context) (int64, error) {
+	return p.total, nil
+}
+
+func (p page) Cursor(i int64) (interface{}, error) {
+	if i < 0 || i >= p.count {
+		return nil, fmt.Errorf("%w: %v", driver.ErrNoCursor, i)
+	}
+
+	return p.cursorFunc(i)
+}
+
+func (p page) NaturalOrder() bool {
+	return p.natural
+}
+
+func (p page) Info() driver.PageInfo {
+	return p.pageInfo
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func clamp(v, lower, upper int64) int64 {
+	return max(lower, min(v, upper))
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,496 @@
+package slice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type Key struct {
+	// Extracts the key value from an element of the slice
+	Value func(v interface{}) interface{}
+	// ASC when false, DESC when true
+	Desc bool
+	// Returns a negative number when a < b, 0 when a == b, a positive number when a > b, defaults to Compare
+	Compare func(a, b interface{}) int
+
+	// Compare is the default one, the kinds of the values are checked beforehand
+	defaultCompare bool
+}
+
+type Options struct {
+	Keys []Key
+	// See base.Driver
+	NaturalOrder bool
+}
+
+// Driver paginating a slice, an array (or a pointer to one of them) in memory, ordered by the keys.
+// The input is not modified, other inputs are rejected when paginating.
+// The cursor value is the list of the key values of the row, the keys should identify rows uniquely.
+// Cursor values that cannot be compared with the key values by Compare are rejected as driver.ErrInvalidCursor
+func New(o Options) driver.Driver {
+	for i := range o.Keys {
+		if o.Keys[i].Compare == nil {
+			o.Keys[i].Compare = Compare
+			o.Keys[i].defaultCompare = true
+		}
+	}
+
+	return base.Driver{
+		CursorEncoder: keysEncoder{o.Keys},
+		ExecutorFactory: func(args base.ExecutorFactoryArgs) base.Executor {
+			return newExecutor(args, o.Keys)
+		},
+		NaturalOrder: o.NaturalOrder,
+	}
+}
+
+type keysEncoder struct {
+	keys []Key
+}
+
+func (e keysEncoder) CursorEncode(input interface{}) (interface{}, error) {
+	return input, nil
+}
+
+func (e keysEncoder) CursorDecode(input interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	values, ok := input.([]interface{})
+	if !ok || len(values) != len(e.keys) {
+		return nil, &driver.Error{Kind: driver.ErrCursorColumnMismatch, Err: errors.New("slice: cursor: decode: expected one value per key")}
+	}
+
+	for i, key := range e.keys {
+		if key.defaultCompare && kindOf(values[i]) == kindUnsupported {
+			return nil, driver.InvalidCursor(fmt.Errorf("slice: cursor: decode: key %v: cannot compare %T", i, values[i]))
+		}
+	}
+
+	return values, nil
+}
+
+type sliceExecutor struct {
+	base.ExecutorFactoryArgs
+	keys []Key
+
+	// Elements of the input, in the order of the traversal
+	elems []reflect.Value
+	// Key values of the elements
+	values [][]interface{}
+	// Index of the first element excluded from TakeFirst and FindNext, see cursor.Cursor.Until
+	end int
+	// Returned by TakeFirst, CountPrevious and FindNext: the input is not a slice, or the cursor values
+	// cannot be compared with the key values
+	err error
+	// Kind of the non nil values of each key, kindNil when unknown, see checkKinds
+	kinds []valueKind
+}
+
+var _ base.Executor = (*sliceExecutor)(nil)
+var _ base.UntilExecutor = (*sliceExecutor)(nil)
+var _ driver.TotalCounter = (*sliceExecutor)(nil)
+
+func newExecutor(args base.ExecutorFactoryArgs, keys []Key) *sliceExecutor {
+	s := reflect.ValueOf(args.Input)
+	for s.Kind() == reflect.Ptr {
+		s = s.Elem()
+	}
+
+	if s.Kind() != reflect.Slice && s.Kind() != reflect.Array {
+		return &sliceExecutor{
+			ExecutorFactoryArgs: args,
+			keys:                keys,
+			err:                 fmt.Errorf("slice: input: expected a slice or an array, got %T", args.Input),
+		}
+	}
+
+	e := &sliceExecutor{
+		ExecutorFactoryArgs: args,
+		keys:                keys,
+		elems:               make([]reflect.Value, s.Len()),
+		values:              make([][]interface{}, s.Len()),
+		end:                 s.Len(),
+	}
+
+	for i := 0; i < s.Len(); i++ {
+		e.elems[i] = s.Index(i)
+
+		e.values[i] = make([]interface{}, len(keys))
+		for j, key := range keys {
+			e.values[i][j] = key.Value(e.elems[i].Interface())
+		}
+	}
+
+	if e.err = e.checkKinds(); e.err != nil {
+		return e
+	}
+
+	sort.Stable(e)
+
+	return e
+}
+
+// Checks that the values of the keys using the default Compare can be compared with each other
+func (e *sliceExecutor) checkKinds() error {
+	e.kinds = make([]valueKind, len(e.keys))
+	for j, key := range e.keys {
+		e.kinds[j] = kindNil
+		if !key.defaultCompare {
+			continue
+		}
+
+		var first interface{}
+		for _, values := range e.values {
+			k := kindOf(values[j])
+			switch {
+			case k == kindUnsupported:
+				return fmt.Errorf("slice: key %v: cannot compare %T", j, values[j])
+			case k == kindNil:
+				continue
+			case e.kinds[j] == kindNil:
+				e.kinds[j] = k
+				first = values[j]
+			case k != e.kinds[j]:
+				return fmt.Errorf("slice: key %v: cannot compare %T and %T", j, first, values[j])
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *sliceExecutor) Len() int {
+	return len(e.elems)
+}
+
+func (e *sliceExecutor) Less(i, j int) bool {
+	return e.compare(e.values[i], e.values[j]) < 0
+}
+
+func (e *sliceExecutor) Swap(i, j int) {
+	e.elems[i], e.elems[j] = e.elems[j], e.elems[i]
+	e.values[i], e.values[j] = e.values[j], e.values[i]
+}
+
+// Compares the key values in the order of the traversal
+func (e *sliceExecutor) compare(a, b []interface{}) int {
+	for i, key := range e.keys {
+		c := key.Compare(a[i], b[i])
+		if key.Desc {
+			c = -c
+		}
+
+		if c != 0 {
+			if e.Cursor.Type == cursor.Before {
+				return -c
+			}
+
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (e *sliceExecutor) Until(until interface{}) base.Executor {
+	ue := *e
+	if ue.err == nil {
+		ue.end, ue.err = e.searchCursor(until.([]interface{}), false)
+	}
+
+	return &ue
+}
+
+func (e *sliceExecutor) TakeFirst(context.Context) (interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if e.end == 0 {
+		return nil, base.ErrNoResult
+	}
+
+	return e.values[0], nil
+}
+
+func (e *sliceExecutor) CountPrevious(_ context.Context, cvalue interface{}) (int64, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	i, err := e.searchCursor(cvalue.([]interface{}), true)
+
+	return int64(i), err
+}
+
+func (e *sliceExecutor) FindNext(_ context.Context, cvalue interface{}, isFirst bool) ([]interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	start, err := e.searchCursor(cvalue.([]interface{}), !isFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	end := start + e.Cursor.Limit + 1
+	if end > e.end {
+		end = e.end
+	}
+	if end < start {
+		end = start
+	}
+
+	values := make([]interface{}, 0, end-start)
+	for _, v := range e.values[start:end] {
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func (e *sliceExecutor) Page(sm, em interface{}) driver.Executor {
+	start := e.search(sm.([]interface{}), false)
+	end := e.search(em.([]interface{}), true)
+
+	if end-start > e.Cursor.Limit {
+		end = start + e.Cursor.Limit
+	}
+
+	return pageExecutor{e.elems[start:end]}
+}
+
+func (e *sliceExecutor) TotalCount(context.Context) (int64, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	return int64(len(e.elems)), nil
+}
+
+// Index of the first element at or after cvalue (strictly after when exclusive)
+func (e *sliceExecutor) search(cvalue []interface{}, exclusive bool) int {
+	return sort.Search(len(e.values), func(i int) bool {
+		c := e.compare(e.values[i], cvalue)
+		if exclusive {
+			return c > 0
+		}
+
+		return c >= 0
+	})
+}
+
+// Same as search, for the values of a cursor decoded from the client, which must be of the kind of the key values
+func (e *sliceExecutor) searchCursor(cvalue []interface{}, exclusive bool) (int, error) {
+	for j, key := range e.keys {
+		if !key.defaultCompare {
+			continue
+		}
+
+		if k := kindOf(cvalue[j]); k != kindNil && e.kinds[j] != kindNil && k != e.kinds[j] {
+			return 0, driver.InvalidCursor(fmt.Errorf("slice: cursor: key %v: cannot compare %T with the key values", j, cvalue[j]))
+		}
+	}
+
+	return e.search(cvalue, exclusive), nil
+}
+
+type pageExecutor struct {
+	elems []reflect.Value
+}
+
+// dst must be a pointer to a slice whose elements can be assigned the elements of the input
+func (p pageExecutor) Query(_ context.Context, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("slice: query: expected pointer to slice, got %T", dst)
+	}
+
+	s := reflect.MakeSlice(v.Elem().Type(), 0, len(p.elems))
+	for _, elem := range p.elems {
+		if !elem.Type().AssignableTo(s.Type().Elem()) {
+			return fmt.Errorf("slice: query: cannot assign %v to %v", elem.Type(), s.Type().Elem())
+		}
+
+		s = reflect.Append(s, elem)
+	}
+
+	v.Elem().Set(s)
+
+	return nil
+}
+
+func (p pageExecutor) Count(context.Context) (int64, error) {
+	return int64(len(p.elems)), nil
+}
+
+// Default Key.Compare, handles nil (sorted first), numbers (regardless of their actual type), strings, bytes
+// (slices and arrays, ex: uuid.UUID), bools and time.Time, including the types based on them (ex: `type Status string`).
+// Panics on other types, or values of different kinds, which the driver reports as errors beforehand
+func Compare(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	ak, bk := kindOf(a), kindOf(b)
+	if ak == kindUnsupported || ak != bk {
+		panic(fmt.Sprintf("slice: cannot compare %T and %T", a, b))
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	switch ak {
+	case kindNumber:
+		c, _ := compareNumbers(a, b)
+		return c
+	case kindString:
+		return compareOrdered(av.String() < bv.String(), av.String() > bv.String())
+	case kindBytes:
+		return bytes.Compare(toBytes(av), toBytes(bv))
+	case kindBool:
+		return compareOrdered(!av.Bool() && bv.Bool(), av.Bool() && !bv.Bool())
+	default:
+		at, bt := toTime(av), toTime(bv)
+		return compareOrdered(at.Before(bt), at.After(bt))
+	}
+}
+
+// Kind of value handled by Compare, values can only be compared to values of the same kind
+type valueKind int
+
+const (
+	kindUnsupported valueKind = iota
+	kindNil
+	kindNumber
+	kindString
+	kindBytes
+	kindBool
+	kindTime
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func kindOf(v interface{}) valueKind {
+	if v == nil {
+		return kindNil
+	}
+
+	rv := reflect.ValueOf(v)
+	if _, ok := numberKind(rv); ok {
+		return kindNumber
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return kindString
+	case reflect.Bool:
+		return kindBool
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return kindBytes
+		}
+	case reflect.Struct:
+		if rv.Type().ConvertibleTo(timeType) {
+			return kindTime
+		}
+	}
+
+	return kindUnsupported
+}
+
+func toBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+
+	return b
+}
+
+func toTime(v reflect.Value) time.Time {
+	return v.Convert(timeType).Interface().(time.Time)
+}
+
+func compareOrdered(lt, gt bool) int {
+	switch {
+	case lt:
+		return -1
+	case gt:
+		return 1
+	}
+
+	return 0
+}
+
+func compareNumbers(a, b interface{}) (int, bool) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	ak, aok := numberKind(av)
+	bk, bok := numberKind(bv)
+	if !aok || !bok {
+		return 0, false
+	}
+
+	switch {
+	case ak == reflect.Int64 && bk == reflect.Int64:
+		return compareOrdered(av.Int() < bv.Int(), av.Int() > bv.Int()), true
+	case ak == reflect.Uint64 && bk == reflect.Uint64:
+		return compareOrdered(av.Uint() < bv.Uint(), av.Uint() > bv.Uint()), true
+	case ak == reflect.Int64 && bk == reflect.Uint64:
+		if av.Int() < 0 {
+			return -1, true
+		}
+		return compareOrdered(uint64(av.Int()) < bv.Uint(), uint64(av.Int()) > bv.Uint()), true
+	case ak == reflect.Uint64 && bk == reflect.Int64:
+		c, _ := compareNumbers(b, a)
+		return -c, true
+	}
+
+	af, bf := toFloat64(av), toFloat64(bv)
+
+	return compareOrdered(af < bf, af > bf), true
+}
+
+// Normalizes the kind of number to Int64, Uint64 or Float64
+func numberKind(v reflect.Value) (reflect.Kind, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint64, true
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64, true
+	}
+
+	return reflect.Invalid, false
+}
+
+func toFloat64(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+
+	return v.Float()
+}
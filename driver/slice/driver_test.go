@@ -0,0 +1,398 @@
+package slice
+
+import (
+	"errors"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type User struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+	Score     *int
+}
+
+func score(v int) *int {
+	return &v
+}
+
+var users = []User{
+	{ID: 1, Name: "u1", CreatedAt: time.Unix(4, 0), Score: score(3)},
+	{ID: 2, Name: "u2", CreatedAt: time.Unix(10, 0)},
+	{ID: 3, Name: "u3", CreatedAt: time.Unix(1, 0), Score: score(1)},
+	{ID: 4, Name: "u4", CreatedAt: time.Unix(6, 0)},
+}
+
+var simpleKeys = []Key{
+	{
+		Value: func(v interface{}) interface{} {
+			return v.(User).CreatedAt
+		},
+	},
+}
+
+var compositeKeys = []Key{
+	{
+		Value: func(v interface{}) interface{} {
+			if s := v.(User).Score; s != nil {
+				return *s
+			}
+			return nil
+		},
+		Desc: true,
+	},
+	{
+		Value: func(v interface{}) interface{} {
+			return v.(User).ID
+		},
+	},
+}
+
+type spec struct {
+	hasPreviousPage bool
+	hasNextPage     bool
+	names           []string
+}
+
+func testPaginator(t *testing.T, keys []Key, typ cursor.Type, limit int, specs []spec) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: keys,
+		}),
+	})
+
+	nextCursor := ""
+	for i, s := range specs {
+		t.Logf("Spec %v\n", i)
+		csr, err := pg.Cursor(nextCursor, typ, limit)
+		require.NoError(t, err)
+
+		res, err := pg.Paginate(csr, users)
+		require.NoError(t, err)
+
+		assert.Equal(t, s.hasPreviousPage, res.PageInfo.HasPreviousPage)
+		assert.Equal(t, s.hasNextPage, res.PageInfo.HasNextPage)
+
+		c, err := res.Count()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(s.names)), c)
+
+		tc, err := res.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), tc)
+
+		var page []User
+		err = res.Query(&page)
+		require.NoError(t, err)
+
+		require.Len(t, page, len(s.names))
+		for i, n := range s.names {
+			assert.Equal(t, n, page[i].Name)
+		}
+
+		nextCursor = res.PageInfo.EndCursor
+	}
+}
+
+func TestPaginator_After(t *testing.T) {
+	testPaginator(t, simpleKeys, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestPaginator_Before(t *testing.T) {
+	testPaginator(t, simpleKeys, cursor.Before, 3, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u3"},
+		},
+	})
+}
+
+func TestPaginator_Composite(t *testing.T) {
+	testPaginator(t, compositeKeys, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u1", "u3"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2", "u4"},
+		},
+	})
+}
+
+func TestPaginator_Empty(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: simpleKeys,
+		}),
+	})
+
+	res, err := pg.Paginate(cursor.Cursor{Type: cursor.After, Limit: 2}, []User{})
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var page []User
+	err = res.Query(&page)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, 0, Compare(int8(42), int64(42)))
+	assert.Equal(t, -1, Compare(int8(-1), uint64(1)))
+	assert.Equal(t, 1, Compare(uint(2), int32(1)))
+	assert.Equal(t, -1, Compare(1, 1.5))
+	assert.Equal(t, -1, Compare(nil, 0))
+	assert.Equal(t, 1, Compare("b", "a"))
+	assert.Equal(t, -1, Compare(time.Unix(1, 0), time.Unix(2, 0)))
+	assert.Equal(t, 1, Compare(status("b"), status("a")))
+	assert.Equal(t, 1, Compare(status("b"), "a"))
+	assert.Equal(t, -1, Compare(rank(1), 2))
+	assert.Equal(t, -1, Compare(id{0, 1}, id{1, 0}))
+	assert.Equal(t, 0, Compare(id{1}, []byte{1, 0}))
+	assert.Panics(t, func() {
+		Compare("a", 1)
+	})
+	assert.Panics(t, func() {
+		Compare(struct{}{}, struct{}{})
+	})
+}
+
+type status string
+
+type rank int
+
+type id [2]byte
+
+func TestPaginator_NamedTypes(t *testing.T) {
+	statuses := []status{"c", "a", "b"}
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: []Key{{
+				Value: func(v interface{}) interface{} {
+					return v
+				},
+			}},
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	var page []status
+	res, err := pg.PaginateInto(csr, statuses, &page)
+	require.NoError(t, err)
+	assert.Equal(t, []status{"a", "b"}, page)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	_, err = pg.PaginateInto(csr, statuses, &page)
+	require.NoError(t, err)
+	assert.Equal(t, []status{"c"}, page)
+
+	// Values which cannot be compared are reported instead of panicking
+	_, err = pg.Paginate(csr, []interface{}{struct{}{}, struct{}{}})
+	assert.EqualError(t, err, "slice: key 0: cannot compare struct {}")
+
+	_, err = pg.Paginate(csr, []interface{}{1, "a"})
+	assert.EqualError(t, err, "slice: key 0: cannot compare int and string")
+}
+
+func TestPaginator_Window(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: simpleKeys,
+		}),
+	})
+
+	// u3, u1, u4, u2
+	res, err := pg.Paginate(cursor.Cursor{Type: cursor.After, Limit: 4}, users)
+	require.NoError(t, err)
+
+	after, err := res.Cursor(0)
+	require.NoError(t, err)
+	before, err := res.Cursor(3)
+	require.NoError(t, err)
+
+	csr, err := pg.WindowCursor(after, before, cursor.After, 3)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(csr, users)
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var page []User
+	err = res.Query(&page)
+	require.NoError(t, err)
+
+	require.Len(t, page, 2)
+	assert.Equal(t, "u1", page[0].Name)
+	assert.Equal(t, "u4", page[1].Name)
+
+	csr, err = pg.WindowCursor(after, before, cursor.Before, 1)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(csr, users)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	err = res.Query(&page)
+	require.NoError(t, err)
+
+	require.Len(t, page, 1)
+	assert.Equal(t, "u4", page[0].Name)
+}
+
+func TestPaginator_InvalidCursor(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: []Key{{
+				Value: func(v interface{}) interface{} {
+					return v.(User).ID
+				},
+			}},
+		}),
+	})
+
+	// Forged by a client
+	data, err := pg.CursorMarshaller.Marshal([]interface{}{"x"})
+	require.NoError(t, err)
+
+	for _, typ := range []cursor.Type{cursor.After, cursor.Before} {
+		csr, err := pg.Cursor(string(data), typ, 2)
+		require.NoError(t, err)
+
+		_, err = pg.Paginate(csr, users)
+		assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor), typ)
+		assert.EqualError(t, err, "invalid cursor: slice: cursor: key 0: cannot compare string with the key values")
+
+		csr, err = pg.WindowCursor("", string(data), typ, 2)
+		require.NoError(t, err)
+
+		_, err = pg.Paginate(csr, users)
+		assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor), typ)
+	}
+}
+
+func TestCursorDecode_Unsupported(t *testing.T) {
+	e := keysEncoder{[]Key{{defaultCompare: true}}}
+
+	_, err := e.CursorDecode([]interface{}{map[string]interface{}{}})
+	assert.True(t, errors.Is(err, go_paginate.ErrInvalidCursor))
+
+	values, err := e.CursorDecode([]interface{}{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a"}, values)
+
+	// Custom comparators are trusted with the cursor values
+	_, err = keysEncoder{[]Key{{}}}.CursorDecode([]interface{}{map[string]interface{}{}})
+	assert.NoError(t, err)
+}
+
+func TestPaginator_CustomCompare_Panics(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: []Key{{
+				Value: func(v interface{}) interface{} {
+					return v.(User).ID
+				},
+				Compare: func(a, b interface{}) int {
+					return a.(int) - b.(int)
+				},
+			}},
+		}),
+	})
+
+	data, err := pg.CursorMarshaller.Marshal([]interface{}{"x"})
+	require.NoError(t, err)
+
+	csr, err := pg.Cursor(string(data), cursor.After, 2)
+	require.NoError(t, err)
+
+	// A bug of the comparator is not reported as an invalid cursor
+	assert.Panics(t, func() {
+		_, _ = pg.Paginate(csr, users)
+	})
+}
+
+type usersSlice []User
+
+type usersList struct {
+	users []User
+}
+
+func TestPaginator_NamedSlice(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: simpleKeys,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, usersSlice(users))
+	require.NoError(t, err)
+
+	var page []User
+	require.NoError(t, res.Query(&page))
+	require.Len(t, page, 2)
+	assert.Equal(t, "u3", page[0].Name)
+	assert.Equal(t, "u1", page[1].Name)
+
+	// Other containers are not supported
+	_, err = pg.Paginate(csr, &usersList{users: users})
+	assert.EqualError(t, err, "slice: input: expected a slice or an array, got *slice.usersList")
+}
+
+func TestPaginator_Empty_Reused(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Keys: simpleKeys,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	page := []User{users[0]}
+	_, err = pg.PaginateInto(csr, []User{}, &page)
+	require.NoError(t, err)
+	assert.Len(t, page, 0)
+
+	page = []User{users[0]}
+	res, err := pg.Paginate(csr, []User{})
+	require.NoError(t, err)
+	require.NoError(t, res.Query(&page))
+	assert.Len(t, page, 0)
+}
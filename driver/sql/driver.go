@@ -0,0 +1,388 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
+	"strings"
+)
+
+type Column = sqlbase.Column
+
+// Satisfied by *sql.DB, *sql.Tx and *sql.Conn (and by extension sqlx.DB and sqlx.Tx)
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// The input to pass to Paginate
+type Input struct {
+	DB Querier
+	// Used as the FROM expression
+	Table string
+	// WHERE fragment filtering the rows to paginate, can be empty
+	Where string
+	Args  []interface{}
+	// Columns selected when querying the page, defaults to "*"
+	Select string
+}
+
+type Options struct {
+	Columns []Column
+	// Scans the rows of the page into dst, defaults to ScanMaps
+	Scan func(rows *sql.Rows, dst interface{}) error
+	// See sqlbase.Options
+	RowValues bool
+	// See base.Driver
+	NaturalOrder bool
+	// See sqlbase.Options
+	SingleQuery bool
+	// Quotes the columns and rebinds the statements (ex: `$1` placeholders), see sqlbase.DialectFor.
+	// Input.Where must use `?` placeholders regardless
+	Dialect sqlbase.Dialect
+	// See sqlbase.Options
+	PrimaryKey []Column
+}
+
+// Scans rows into dst, which must be a *[]map[string]interface{}
+func ScanMaps(rows *sql.Rows, dst interface{}) error {
+	ms, ok := dst.(*[]map[string]interface{})
+	if !ok {
+		return fmt.Errorf("sql: scan: unsupported destination %T", dst)
+	}
+
+	r, err := RowsMap(rows)
+	if err != nil {
+		return err
+	}
+
+	*ms = r
+
+	return nil
+}
+
+func New(o Options) driver.Driver {
+	return sqlbase.New(baseOptions(o))
+}
+
+// Same as New, validating the options first, see sqlbase.Options.Validate
+func NewValidated(o Options) (driver.Driver, error) {
+	return sqlbase.NewValidated(baseOptions(o))
+}
+
+func baseOptions(o Options) sqlbase.Options {
+	scanMaps := o.Scan == nil
+	if scanMaps {
+		o.Scan = ScanMaps
+	}
+
+	return sqlbase.Options{
+		Columns:      o.Columns,
+		RowValues:    o.RowValues,
+		NaturalOrder: o.NaturalOrder,
+		SingleQuery:  o.SingleQuery,
+		Dialect:      o.Dialect,
+		PrimaryKey:   o.PrimaryKey,
+		ExecutorFactory: func(args sqlbase.ExecutorFactoryArgs) sqlbase.Executor {
+			input := args.Input.(Input)
+
+			columnWrapper := func(col string) string {
+				return col
+			}
+			if args.Dialect.Quote != nil {
+				columnWrapper = args.Dialect.Quote
+			}
+
+			orders, ordersArgs := sqlbase.OrderBy(args.Columns, args.Cursor.Type, columnWrapper)
+			selects, selectsArgs := sqlbase.Select(args.Columns, columnWrapper)
+			aliased, aliasedArgs := sqlbase.SelectAliased(args.Columns, columnWrapper)
+
+			return sqlExecutor{
+				input:         input,
+				rebind:        args.Dialect.Rebind,
+				scan:          o.Scan,
+				scanMaps:      scanMaps,
+				columnWrapper: columnWrapper,
+				orders:        orders,
+				ordersArgs:    ordersArgs,
+				selects:       selects,
+				selectsArgs:   selectsArgs,
+				aliased:       aliased,
+				aliasedArgs:   aliasedArgs,
+			}
+		},
+	}
+}
+
+var _ sqlbase.SingleQueryExecutor = (*sqlExecutor)(nil)
+var _ sqlbase.IntoExecutor = (*sqlExecutor)(nil)
+
+type sqlExecutor struct {
+	input Input
+	// See sqlbase.Dialect
+	rebind func(query string) string
+	scan   func(rows *sql.Rows, dst interface{}) error
+	// Whether scan is ScanMaps
+	scanMaps      bool
+	columnWrapper func(col string) string
+
+	orders      string
+	ordersArgs  []interface{}
+	selects     string
+	selectsArgs []interface{}
+	// Columns selected along with the rows, see sqlbase.SelectAliased
+	aliased     string
+	aliasedArgs []interface{}
+}
+
+func (e sqlExecutor) WrapColumn(col string) string {
+	return e.columnWrapper(col)
+}
+
+func (e sqlExecutor) TakeFirst(ctx context.Context) (map[string]interface{}, error) {
+	ms, err := e.findMap(ctx, e.statement(e.selects, e.selectsArgs).ordered(e).limited(1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ms) == 0 {
+		return nil, base.ErrNoResult
+	}
+
+	return ms[0], nil
+}
+
+func (e sqlExecutor) TotalCount(ctx context.Context) (int64, error) {
+	return e.count(ctx, e.statement("COUNT(*)", nil))
+}
+
+func (e sqlExecutor) CountPrevious(ctx context.Context, where string, args []interface{}) (int64, error) {
+	return e.count(ctx, e.statement("COUNT(*)", nil).where(where, args))
+}
+
+func (e sqlExecutor) FindNext(ctx context.Context, query string, args []interface{}, limit int) ([]map[string]interface{}, error) {
+	return e.findMap(ctx, e.statement(e.selects, e.selectsArgs).where(query, args).ordered(e).limited(limit))
+}
+
+func (e sqlExecutor) FindNextWithPrevious(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int) ([]map[string]interface{}, error) {
+	selects, selectsArgs := e.withPrevious(e.selects, e.selectsArgs, previous, previousArgs)
+
+	return e.findMap(ctx, e.statement(selects, selectsArgs).where(query, args).ordered(e).limited(limit))
+}
+
+// The rows can only be scanned into a *[]map[string]interface{}, with the default Options.Scan
+func (e sqlExecutor) CanScanInto(dst interface{}) bool {
+	_, ok := dst.(*[]map[string]interface{})
+
+	return ok && e.scanMaps
+}
+
+func (e sqlExecutor) FindNextInto(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int, dst interface{}) ([]map[string]interface{}, error) {
+	selects, selectsArgs := e.withPrevious(e.inputSelects()+", "+e.aliased, e.aliasedArgs, previous, previousArgs)
+
+	ms, err := e.findMap(ctx, e.statement(selects, selectsArgs).where(query, args).ordered(e).limited(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]interface{}, len(ms))
+	for i, m := range ms {
+		rows[i] = make(map[string]interface{}, len(m))
+		for k, v := range m {
+			if !sqlbase.IsAlias(k) {
+				rows[i][k] = v
+			}
+		}
+	}
+
+	*dst.(*[]map[string]interface{}) = rows
+
+	return ms, nil
+}
+
+// Appends to selects whether any row matches previous (unless empty), as a sqlbase.HasPreviousAlias column
+func (e sqlExecutor) withPrevious(selects string, args []interface{}, previous string, previousArgs []interface{}) (string, []interface{}) {
+	if previous == "" {
+		return selects, args
+	}
+
+	pst := e.statement("1", nil).where(previous, previousArgs)
+
+	selects = fmt.Sprintf("%v, EXISTS (%v) AS %v", selects, pst.SQL(), sqlbase.HasPreviousAlias)
+	args = append(args[:len(args):len(args)], pst.args...)
+
+	return selects, args
+}
+
+func (e sqlExecutor) Page(where string, args []interface{}, limit int) driver.Executor {
+	return pageExecutor{
+		executor: e,
+		where:    where,
+		args:     args,
+		limit:    limit,
+	}
+}
+
+func (e sqlExecutor) statement(selects string, args []interface{}) statement {
+	st := statement{
+		selects: selects,
+		from:    e.input.Table,
+	}
+	st.args = append(st.args, args...)
+
+	return st.where(e.input.Where, e.input.Args)
+}
+
+// Columns selected when querying the page, see Input.Select
+func (e sqlExecutor) inputSelects() string {
+	if e.input.Select == "" {
+		return "*"
+	}
+
+	return e.input.Select
+}
+
+func (e sqlExecutor) query(ctx context.Context, st statement) (*sql.Rows, error) {
+	query := st.SQL()
+	if e.rebind != nil {
+		query = e.rebind(query)
+	}
+
+	return e.input.DB.QueryContext(ctx, query, st.args...)
+}
+
+func (e sqlExecutor) findMap(ctx context.Context, st statement) ([]map[string]interface{}, error) {
+	rows, err := e.query(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ms, err := RowsMap(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms, rows.Err()
+}
+
+func (e sqlExecutor) count(ctx context.Context, st statement) (int64, error) {
+	rows, err := e.query(ctx, st)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+
+		return 0, errors.New("sql: count: no row returned")
+	}
+
+	var c int64
+	if err := rows.Scan(&c); err != nil {
+		return 0, err
+	}
+
+	return c, nil
+}
+
+type pageExecutor struct {
+	executor sqlExecutor
+	where    string
+	args     []interface{}
+	limit    int
+}
+
+func (p pageExecutor) Query(ctx context.Context, dst interface{}) error {
+	e := p.executor
+
+	st := e.statement(e.inputSelects(), nil).where(p.where, p.args).ordered(e).limited(p.limit)
+
+	rows, err := e.query(ctx, st)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := e.scan(rows, dst); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+func (p pageExecutor) Count(ctx context.Context) (int64, error) {
+	e := p.executor
+
+	return e.count(ctx, e.statement("COUNT(*)", nil).where(p.where, p.args))
+}
+
+type statement struct {
+	selects string
+	from    string
+	wheres  []string
+	orders  string
+	limit   int
+	// Ordered as they appear in the statement
+	args []interface{}
+}
+
+func (s statement) where(where string, args []interface{}) statement {
+	if where == "" {
+		return s
+	}
+
+	s.wheres = append(s.wheres[:len(s.wheres):len(s.wheres)], where)
+	s.args = append(s.args[:len(s.args):len(s.args)], args...)
+
+	return s
+}
+
+// Must be called after all the where clauses have been added
+func (s statement) ordered(e sqlExecutor) statement {
+	s.orders = e.orders
+	s.args = append(s.args[:len(s.args):len(s.args)], e.ordersArgs...)
+
+	return s
+}
+
+func (s statement) limited(limit int) statement {
+	s.limit = limit
+
+	return s
+}
+
+func (s statement) SQL() string {
+	var sb strings.Builder
+
+	sb.WriteString("SELECT ")
+	sb.WriteString(s.selects)
+	sb.WriteString(" FROM ")
+	sb.WriteString(s.from)
+
+	for i, w := range s.wheres {
+		if i == 0 {
+			sb.WriteString(" WHERE ")
+		} else {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString("(" + w + ")")
+	}
+
+	if s.orders != "" {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(s.orders)
+	}
+
+	if s.limit > 0 {
+		fmt.Fprintf(&sb, " LIMIT %v", s.limit)
+	}
+
+	return sb.String()
+}
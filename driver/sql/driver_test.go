@@ -0,0 +1,809 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver/sqlbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func setup(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, created_at INTEGER, score INTEGER, deleted INTEGER)")
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (id, name, created_at, score, deleted) VALUES
+		(1, 'u1', 4, 3, 0),
+		(2, 'u2', 10, NULL, 0),
+		(3, 'u3', 1, 1, 0),
+		(4, 'u4', 6, NULL, 0),
+		(5, 'u5', 2, NULL, 1)`)
+	require.NoError(t, err)
+
+	return db
+}
+
+type spec struct {
+	hasPreviousPage bool
+	hasNextPage     bool
+	names           []string
+}
+
+func testPaginator(t *testing.T, columns []Column, typ cursor.Type, limit int, specs []spec) {
+	testPaginatorOptions(t, Options{Columns: columns}, typ, limit, specs)
+}
+
+func testPaginatorOptions(t *testing.T, o Options, typ cursor.Type, limit int, specs []spec) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(o),
+	})
+
+	input := Input{
+		DB:    db,
+		Table: "users",
+		Where: "deleted = ?",
+		Args:  []interface{}{0},
+	}
+
+	nextCursor := ""
+	for i, s := range specs {
+		t.Logf("Spec %v\n", i)
+		csr, err := pg.Cursor(nextCursor, typ, limit)
+		require.NoError(t, err)
+
+		res, err := pg.Paginate(csr, input)
+		require.NoError(t, err)
+
+		assert.Equal(t, s.hasPreviousPage, res.PageInfo.HasPreviousPage)
+		assert.Equal(t, s.hasNextPage, res.PageInfo.HasNextPage)
+
+		c, err := res.Count()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(s.names)), c)
+
+		tc, err := res.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), tc)
+
+		var users []map[string]interface{}
+		err = res.Query(&users)
+		require.NoError(t, err)
+
+		require.Len(t, users, len(s.names))
+		for i, n := range s.names {
+			assert.Equal(t, n, users[i]["name"])
+		}
+
+		nextCursor = res.PageInfo.EndCursor
+	}
+}
+
+var simpleColumns = []Column{
+	{
+		Name: "created_at",
+	},
+}
+
+var compositeColumns = []Column{
+	{
+		Name: "created_at",
+		Desc: true,
+	},
+	{
+		Name: "id",
+	},
+}
+
+var nullableColumns = []Column{
+	{
+		Name:     "score",
+		Nullable: true,
+	},
+	{
+		Name: "id",
+	},
+}
+
+var nullableFirstColumns = []Column{
+	{
+		Name:       "score",
+		Desc:       true,
+		Nullable:   true,
+		NullsFirst: true,
+	},
+	{
+		Name: "id",
+	},
+}
+
+func TestDriver_Empty(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, Input{
+		DB:    db,
+		Table: "users",
+		Where: "1=0",
+	})
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+	assert.Empty(t, res.PageInfo.StartCursor)
+	assert.Empty(t, res.PageInfo.EndCursor)
+}
+
+func TestDriver_After_Simple(t *testing.T) {
+	testPaginator(t, simpleColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestDriver_Before_Composite(t *testing.T) {
+	testPaginator(t, compositeColumns, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u4", "u2"},
+		},
+	})
+}
+
+func TestDriver_Tx(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 3)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, Input{
+		DB:     tx,
+		Table:  "users",
+		Select: "name",
+	})
+	require.NoError(t, err)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	var users []map[string]interface{}
+	err = res.Query(&users)
+	require.NoError(t, err)
+
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "u3"},
+		{"name": "u5"},
+		{"name": "u1"},
+	}, users)
+}
+
+func TestDriver_Context(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = pg.PaginateContext(ctx, csr, Input{
+		DB:    db,
+		Table: "users",
+	})
+	assert.True(t, errors.Is(err, context.Canceled), err)
+}
+
+func TestDriver_After_NullsLast(t *testing.T) {
+	testPaginator(t, nullableColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2", "u4"},
+		},
+	})
+}
+
+func TestDriver_Before_NullsLast(t *testing.T) {
+	testPaginator(t, nullableColumns, cursor.Before, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u4", "u2"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u1", "u3"},
+		},
+	})
+}
+
+func TestDriver_After_NullsFirst(t *testing.T) {
+	testPaginator(t, nullableFirstColumns, cursor.After, 2, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u2", "u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u1", "u3"},
+		},
+	})
+}
+
+func TestDriver_Before_NullsFirst(t *testing.T) {
+	testPaginator(t, nullableFirstColumns, cursor.Before, 3, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1", "u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2"},
+		},
+	})
+}
+
+func TestDriver_Window(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+		}),
+	})
+
+	input := Input{
+		DB:    db,
+		Table: "users",
+		Where: "deleted = ?",
+		Args:  []interface{}{0},
+	}
+
+	csr, err := pg.Cursor("", cursor.After, 4)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, input)
+	require.NoError(t, err)
+
+	// u3, u1, u4, u2
+	cursors := make([]string, 4)
+	for i := range cursors {
+		cursors[i], err = res.Cursor(int64(i))
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name        string
+		after       string
+		before      string
+		typ         cursor.Type
+		limit       int
+		hasPrevious bool
+		hasNext     bool
+		names       []string
+	}{
+		{name: "first before", before: cursors[3], typ: cursor.After, limit: 2, hasPrevious: false, hasNext: true, names: []string{"u3", "u1"}},
+		{name: "after before", after: cursors[1], before: cursors[3], typ: cursor.After, limit: 2, hasPrevious: true, hasNext: false, names: []string{"u4"}},
+		{name: "last after", after: cursors[0], typ: cursor.Before, limit: 1, hasPrevious: false, hasNext: true, names: []string{"u2"}},
+		{name: "last after before", after: cursors[0], before: cursors[2], typ: cursor.Before, limit: 2, hasPrevious: true, hasNext: false, names: []string{"u1"}},
+		{name: "empty", after: cursors[1], before: cursors[2], typ: cursor.After, limit: 2, hasPrevious: true, hasNext: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			csr, err := pg.WindowCursor(test.after, test.before, test.typ, test.limit)
+			require.NoError(t, err)
+
+			res, err := pg.Paginate(csr, input)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.hasPrevious, res.PageInfo.HasPreviousPage)
+			assert.Equal(t, test.hasNext, res.PageInfo.HasNextPage)
+
+			var users []map[string]interface{}
+			err = res.Query(&users)
+			require.NoError(t, err)
+
+			require.Len(t, users, len(test.names))
+			for i, n := range test.names {
+				assert.Equal(t, n, users[i]["name"])
+			}
+		})
+	}
+}
+
+func TestDriver_After_Limit1(t *testing.T) {
+	testPaginator(t, simpleColumns, cursor.After, 1, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     true,
+			names:           []string{"u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     true,
+			names:           []string{"u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2"},
+		},
+	})
+}
+
+func TestDriver_Before_NaturalOrder(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns:      simpleColumns,
+			NaturalOrder: true,
+		}),
+	})
+
+	input := Input{
+		DB:    db,
+		Table: "users",
+		Where: "deleted = ?",
+		Args:  []interface{}{0},
+	}
+
+	specs := []spec{
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u1", "u4", "u2"},
+		},
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3"},
+		},
+	}
+
+	nextCursor := ""
+	for i, s := range specs {
+		t.Logf("Spec %v\n", i)
+		csr, err := pg.Cursor(nextCursor, cursor.Before, 3)
+		require.NoError(t, err)
+
+		res, err := pg.Paginate(csr, input)
+		require.NoError(t, err)
+
+		assert.True(t, res.NaturalOrder)
+		assert.Equal(t, s.hasPreviousPage, res.PageInfo.HasPreviousPage)
+		assert.Equal(t, s.hasNextPage, res.PageInfo.HasNextPage)
+
+		sc, err := res.Cursor(0)
+		require.NoError(t, err)
+		assert.Equal(t, sc, res.PageInfo.StartCursor)
+		ec, err := res.Cursor(int64(len(s.names) - 1))
+		require.NoError(t, err)
+		assert.Equal(t, ec, res.PageInfo.EndCursor)
+
+		var users []map[string]interface{}
+		err = res.Query(&users)
+		require.NoError(t, err)
+
+		require.Len(t, users, len(s.names))
+		for i, n := range s.names {
+			assert.Equal(t, n, users[i]["name"])
+		}
+
+		// Going backward continues from the start of the page
+		nextCursor = res.PageInfo.StartCursor
+	}
+}
+
+// Counts the queries executed
+type countingQuerier struct {
+	Querier
+	count int
+}
+
+func (q *countingQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	q.count++
+
+	return q.Querier.QueryContext(ctx, query, args...)
+}
+
+func TestDriver_SingleQuery(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns:     compositeColumns,
+			SingleQuery: true,
+		}),
+	})
+
+	q := &countingQuerier{Querier: db}
+	input := Input{
+		DB:    q,
+		Table: "users",
+		Where: "deleted = ?",
+		Args:  []interface{}{0},
+	}
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, input)
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.count)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	q.count = 0
+	res, err = pg.Paginate(csr, input)
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.count)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	// Empty page, the previous rows are counted separately
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	q.count = 0
+	res, err = pg.Paginate(csr, input)
+	require.NoError(t, err)
+	assert.Equal(t, 2, q.count)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+}
+
+func TestDriver_SingleQuery_Before_NullsFirst(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: nullableFirstColumns, SingleQuery: true}, cursor.Before, 3, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3", "u1", "u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2"},
+		},
+	})
+}
+
+func TestDriver_SingleQuery_After_Limit1(t *testing.T) {
+	testPaginatorOptions(t, Options{Columns: simpleColumns, SingleQuery: true}, cursor.After, 1, []spec{
+		{
+			hasPreviousPage: false,
+			hasNextPage:     true,
+			names:           []string{"u3"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     true,
+			names:           []string{"u1"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     true,
+			names:           []string{"u4"},
+		},
+		{
+			hasPreviousPage: true,
+			hasNextPage:     false,
+			names:           []string{"u2"},
+		},
+	})
+}
+
+func TestDriver_PaginateInto(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: compositeColumns,
+		}),
+	})
+
+	q := &countingQuerier{Querier: db}
+	input := Input{
+		DB:     q,
+		Table:  "users",
+		Where:  "deleted = ?",
+		Args:   []interface{}{0},
+		Select: "id, name",
+	}
+
+	csr, err := pg.Cursor("", cursor.After, 3)
+	require.NoError(t, err)
+
+	var users []map[string]interface{}
+	res, err := pg.PaginateInto(csr, input, &users)
+	require.NoError(t, err)
+	// TakeFirst and FindNext
+	assert.Equal(t, 2, q.count)
+
+	assert.False(t, res.PageInfo.HasPreviousPage)
+	assert.True(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, users, 3)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(2), "name": "u2"},
+		{"id": int64(4), "name": "u4"},
+		{"id": int64(1), "name": "u1"},
+	}, users)
+
+	var queried []map[string]interface{}
+	require.NoError(t, res.Query(&queried))
+	assert.Equal(t, queried, users)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 3)
+	require.NoError(t, err)
+
+	q.count = 0
+	res, err = pg.PaginateInto(csr, input, &users)
+	require.NoError(t, err)
+	assert.Equal(t, 2, q.count)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "u3", users[0]["name"])
+}
+
+func TestDriver_PaginateInto_CustomScan(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: simpleColumns,
+			Scan: func(rows *sql.Rows, dst interface{}) error {
+				names := dst.(*[]string)
+				for rows.Next() {
+					var id int64
+					var name string
+					if err := rows.Scan(&id, &name); err != nil {
+						return err
+					}
+
+					*names = append(*names, name)
+				}
+
+				return nil
+			},
+		}),
+	})
+
+	q := &countingQuerier{Querier: db}
+	input := Input{
+		DB:     q,
+		Table:  "users",
+		Where:  "deleted = ?",
+		Args:   []interface{}{0},
+		Select: "id, name",
+	}
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	// The rows are queried again through the page
+	var names []string
+	_, err = pg.PaginateInto(csr, input, &names)
+	require.NoError(t, err)
+	assert.Equal(t, 3, q.count)
+
+	assert.Equal(t, []string{"u3", "u1"}, names)
+}
+
+// Records the queries executed
+type recordingQuerier struct {
+	Querier
+	queries []string
+}
+
+func (q *recordingQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	q.queries = append(q.queries, query)
+
+	return q.Querier.QueryContext(ctx, query, args...)
+}
+
+func TestDriver_Dialect(t *testing.T) {
+	db := setup(t)
+	defer db.Close()
+
+	// SQLite accepts the `$n` placeholders as well
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: New(Options{
+			Columns: compositeColumns,
+			Dialect: sqlbase.DialectPostgres,
+		}),
+	})
+
+	q := &recordingQuerier{Querier: db}
+	input := Input{
+		DB:    q,
+		Table: "users",
+		Where: "deleted = ?",
+		Args:  []interface{}{0},
+	}
+
+	csr, err := pg.Cursor("", cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err := pg.Paginate(csr, input)
+	require.NoError(t, err)
+
+	csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 2)
+	require.NoError(t, err)
+
+	res, err = pg.Paginate(csr, input)
+	require.NoError(t, err)
+
+	assert.True(t, res.PageInfo.HasPreviousPage)
+	assert.False(t, res.PageInfo.HasNextPage)
+
+	var users []map[string]interface{}
+	require.NoError(t, res.Query(&users))
+	require.Len(t, users, 2)
+	assert.Equal(t, "u1", users[0]["name"])
+	assert.Equal(t, "u3", users[1]["name"])
+
+	last := q.queries[len(q.queries)-1]
+	assert.Contains(t, last, `ORDER BY "created_at" desc,"id" asc`)
+	assert.Contains(t, last, "$1")
+	assert.NotContains(t, last, "?")
+}
+
+// Encodes the ids as strings, to check that the codec of the primary key is applied both ways
+type idCodec struct{}
+
+func (idCodec) Encode(v interface{}) (interface{}, error) {
+	return fmt.Sprintf("id-%v", v), nil
+}
+
+func (idCodec) Decode(v interface{}) (interface{}, error) {
+	var id int64
+	if _, err := fmt.Sscanf(fmt.Sprint(v), "id-%d", &id); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+func TestDriver_PrimaryKey(t *testing.T) {
+	for name, primaryKey := range map[string]Column{
+		"plain": {Name: "id", Codec: sqlbase.Int64Codec()},
+		"codec": {Name: "id", Codec: idCodec{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := setup(t)
+			defer db.Close()
+
+			// u1, u3 and u4 share the same time
+			_, err := db.Exec("UPDATE users SET created_at = 4 WHERE name IN ('u3', 'u4')")
+			require.NoError(t, err)
+
+			pg := go_paginate.New(go_paginate.Options{
+				Driver: New(Options{
+					Columns:    []Column{{Name: "created_at", Desc: true}},
+					PrimaryKey: []Column{primaryKey},
+				}),
+			})
+
+			input := Input{
+				DB:    db,
+				Table: "users",
+				Where: "deleted = ?",
+				Args:  []interface{}{0},
+			}
+
+			names := make([]interface{}, 0)
+
+			csr, err := pg.Cursor("", cursor.After, 1)
+			require.NoError(t, err)
+			for i := 0; i < 10; i++ {
+				var users []map[string]interface{}
+				res, err := pg.PaginateInto(csr, input, &users)
+				require.NoError(t, err)
+
+				for _, u := range users {
+					names = append(names, u["name"])
+				}
+
+				if !res.PageInfo.HasNextPage {
+					break
+				}
+
+				csr, err = pg.Cursor(res.PageInfo.EndCursor, cursor.After, 1)
+				require.NoError(t, err)
+			}
+
+			// The primary key follows the direction of the last column
+			assert.Equal(t, []interface{}{"u2", "u4", "u3", "u1"}, names)
+
+			// The cursor carries the values of the primary key in the form of its codec
+			res, err := pg.Paginate(csr, input)
+			require.NoError(t, err)
+			values, err := pg.CursorMarshaller.Unmarshal([]byte(res.PageInfo.EndCursor))
+			require.NoError(t, err)
+			id, err := primaryKey.Codec.Encode(1)
+			require.NoError(t, err)
+			assert.Equal(t, id, values.([]interface{})[1])
+		})
+	}
+}
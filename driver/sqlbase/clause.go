@@ -0,0 +1,76 @@
+package sqlbase
+
+import (
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/cursor"
+)
+
+// Generates the ORDER BY expression of the columns for the given cursor type,
+// wrap is applied to each column name (see Executor.WrapColumn)
+func OrderBy(columns []Column, t cursor.Type, wrap func(col string) string) (string, []interface{}) {
+	sql := ""
+	args := make([]interface{}, 0)
+
+	for _, column := range columns {
+		wc := column.wrap(wrap)
+
+		col, vars := wc.Reference(wc)
+
+		if sql != "" {
+			sql += ","
+		}
+		if column.Nullable {
+			sql += fmt.Sprintf("%v IS NULL %v,", col, column.NullsOrder(t))
+			args = append(args, vars...)
+		}
+		sql += fmt.Sprintf("%v %v", col, column.Order(t))
+		args = append(args, vars...)
+	}
+
+	return sql, args
+}
+
+// Generates the SELECT expression of the columns, each column is aliased to its name (without the dots
+// of a qualified name) so that the resulting rows can be used as cursor values
+func Select(columns []Column, wrap func(col string) string) (string, []interface{}) {
+	sql := ""
+	args := make([]interface{}, 0)
+
+	for _, column := range columns {
+		wc := column.wrap(wrap)
+
+		col, vars := wc.Reference(wc)
+
+		if sql != "" {
+			sql += ","
+		}
+		sql += col
+		if column.key() != col {
+			sql += " AS " + column.key()
+		}
+		args = append(args, vars...)
+	}
+
+	return sql, args
+}
+
+// Generates the SELECT expression of the columns, each column is aliased with ColumnAlias
+// so that they can be selected along with the rows (ex: `*, created_at AS paginate_cursor_created_at`)
+func SelectAliased(columns []Column, wrap func(col string) string) (string, []interface{}) {
+	sql := ""
+	args := make([]interface{}, 0)
+
+	for _, column := range columns {
+		wc := column.wrap(wrap)
+
+		col, vars := wc.Reference(wc)
+
+		if sql != "" {
+			sql += ","
+		}
+		sql += col + " AS " + ColumnAlias(column)
+		args = append(args, vars...)
+	}
+
+	return sql, args
+}
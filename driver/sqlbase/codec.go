@@ -0,0 +1,205 @@
+package sqlbase
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Converts the values of a column from/to their cursor representation, allows to preserve
+// the Go type of the values through the cursor Marshaller (which can only be expected
+// to handle literals, arrays and maps)
+type Codec interface {
+	// Converts a value read from the database into its cursor representation
+	Encode(v interface{}) (interface{}, error)
+	// Converts the cursor representation back into the value passed as query argument
+	Decode(v interface{}) (interface{}, error)
+}
+
+// Preserves the instant (to the nanosecond) and the location of time.Time values
+func TimeCodec() Codec {
+	return timeCodec{}
+}
+
+type timeCodec struct{}
+
+func (timeCodec) Encode(v interface{}) (interface{}, error) {
+	t, ok := v.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("sqlbase: codec: time: unsupported value %T", v)
+	}
+
+	name, offset := t.Zone()
+
+	return []interface{}{t.Unix(), int64(t.Nanosecond()), t.Location().String(), name, int64(offset)}, nil
+}
+
+func (timeCodec) Decode(v interface{}) (interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 5 {
+		return nil, fmt.Errorf("sqlbase: codec: time: unsupported value %T", v)
+	}
+
+	sec, err := toInt64(a[0])
+	if err != nil {
+		return nil, err
+	}
+	nsec, err := toInt64(a[1])
+	if err != nil {
+		return nil, err
+	}
+	locName, _ := a[2].(string)
+	zoneName, _ := a[3].(string)
+	offset, err := toInt64(a[4])
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(locName)
+	if err != nil {
+		loc = time.FixedZone(zoneName, int(offset))
+	}
+
+	return time.Unix(sec, nsec).In(loc), nil
+}
+
+// Decodes integers as int64, whatever their size in the cursor
+func Int64Codec() Codec {
+	return int64Codec{}
+}
+
+type int64Codec struct{}
+
+func (int64Codec) Encode(v interface{}) (interface{}, error) {
+	return toInt64(v)
+}
+
+func (int64Codec) Decode(v interface{}) (interface{}, error) {
+	return toInt64(v)
+}
+
+// Decodes numbers as float64
+func Float64Codec() Codec {
+	return float64Codec{}
+}
+
+type float64Codec struct{}
+
+func (float64Codec) Encode(v interface{}) (interface{}, error) {
+	return toFloat64(v)
+}
+
+func (float64Codec) Decode(v interface{}) (interface{}, error) {
+	return toFloat64(v)
+}
+
+// Decodes values as string, []byte values (as returned by some drivers for text columns) are converted
+func StringCodec() Codec {
+	return stringCodec{}
+}
+
+type stringCodec struct{}
+
+func (stringCodec) Encode(v interface{}) (interface{}, error) {
+	return toString(v)
+}
+
+func (stringCodec) Decode(v interface{}) (interface{}, error) {
+	return toString(v)
+}
+
+// Decodes values as []byte (ex: binary UUIDs)
+func BytesCodec() Codec {
+	return bytesCodec{}
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(v interface{}) (interface{}, error) {
+	s, err := toString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+func (c bytesCodec) Decode(v interface{}) (interface{}, error) {
+	return c.Encode(v)
+}
+
+// Stores the values in their text form, decodes them into the value returned by newValue
+// (ex: decimal or UUID types implementing encoding.TextMarshaler and encoding.TextUnmarshaler)
+func TextCodec(newValue func() encoding.TextUnmarshaler) Codec {
+	return textCodec{newValue}
+}
+
+type textCodec struct {
+	newValue func() encoding.TextUnmarshaler
+}
+
+func (textCodec) Encode(v interface{}) (interface{}, error) {
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return string(b), nil
+	}
+
+	return toString(v)
+}
+
+func (c textCodec) Decode(v interface{}) (interface{}, error) {
+	s, err := toString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	u := c.newValue()
+	if err := u.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func toInt64(v interface{}) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("sqlbase: codec: expected integer, got %T", v)
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, fmt.Errorf("sqlbase: codec: expected number, got %T", v)
+	}
+
+	return float64(i), nil
+}
+
+func toString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+
+	return "", fmt.Errorf("sqlbase: codec: expected string or []byte, got %T", v)
+}
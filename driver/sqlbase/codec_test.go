@@ -0,0 +1,119 @@
+package sqlbase
+
+import (
+	"database/sql"
+	"encoding"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// Encodes input through the encoder and the marshaller, and back
+func roundTrip(t *testing.T, columns []Column, input map[string]interface{}) map[string]interface{} {
+	e := cursorEncoder{columns}
+	m := cursor.MsgPack()
+
+	encoded, err := e.CursorEncode(input)
+	require.NoError(t, err)
+
+	data, err := m.Marshal(encoded)
+	require.NoError(t, err)
+
+	decoded, err := m.Unmarshal(data)
+	require.NoError(t, err)
+
+	output, err := e.CursorDecode(decoded)
+	require.NoError(t, err)
+
+	return output.(map[string]interface{})
+}
+
+func TestCodec_Time(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	for _, v := range []time.Time{
+		time.Date(2020, 11, 12, 13, 14, 15, 123456789, time.UTC),
+		time.Date(2020, 11, 12, 13, 14, 15, 123456789, loc),
+		time.Date(2020, 11, 12, 13, 14, 15, 123456789, time.FixedZone("X", 3600)),
+	} {
+		output := roundTrip(t, []Column{{Name: "t", Codec: TimeCodec()}}, map[string]interface{}{"t": v})
+
+		ot := output["t"].(time.Time)
+		assert.True(t, v.Equal(ot), "%v != %v", v, ot)
+		assert.Equal(t, v.Location().String(), ot.Location().String())
+		assert.Equal(t, v.String(), ot.String())
+	}
+}
+
+func TestCodec_Primitives(t *testing.T) {
+	columns := []Column{
+		{Name: "i", Codec: Int64Codec()},
+		{Name: "f", Codec: Float64Codec()},
+		{Name: "s", Codec: StringCodec()},
+		{Name: "b", Codec: BytesCodec()},
+		{Name: "n", Codec: Int64Codec()},
+		{Name: "raw"},
+	}
+
+	output := roundTrip(t, columns, map[string]interface{}{
+		"i":   42,
+		"f":   42,
+		"s":   []byte("hey"),
+		"b":   []byte{1, 2, 3},
+		"n":   nil,
+		"raw": 42,
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"i":   int64(42),
+		"f":   float64(42),
+		"s":   "hey",
+		"b":   []byte{1, 2, 3},
+		"n":   nil,
+		"raw": int8(42),
+	}, output)
+}
+
+func TestCodec_Valuer(t *testing.T) {
+	columns := []Column{
+		{Name: "valid", Codec: TimeCodec()},
+		{Name: "null", Codec: TimeCodec()},
+		{Name: "s"},
+	}
+
+	v := time.Date(2020, 11, 12, 13, 14, 15, 123456789, time.UTC)
+
+	output := roundTrip(t, columns, map[string]interface{}{
+		"valid": sql.NullTime{Time: v, Valid: true},
+		"null":  sql.NullTime{},
+		"s":     sql.NullString{String: "hey", Valid: true},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"valid": v,
+		"null":  nil,
+		"s":     "hey",
+	}, output)
+}
+
+func TestCodec_Text(t *testing.T) {
+	columns := []Column{
+		{
+			Name: "d",
+			Codec: TextCodec(func() encoding.TextUnmarshaler {
+				return new(big.Rat)
+			}),
+		},
+	}
+
+	d, ok := new(big.Rat).SetString("1234567890.0123456789")
+	require.True(t, ok)
+
+	output := roundTrip(t, columns, map[string]interface{}{"d": d})
+
+	assert.Equal(t, 0, d.Cmp(output["d"].(*big.Rat)))
+}
@@ -1,7 +1,11 @@
 package sqlbase
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"github.com/raphaelvigee/go-paginate/cursor"
+	"hash/fnv"
+	"strings"
 )
 
 type Column struct {
@@ -13,6 +17,35 @@ type Column struct {
 	Reference func(column Column) (string, []interface{})
 	// Prints the placeholder for prepared request, defaults to "?"
 	Placeholder func(column Column) string
+	// Set to true if the column can contain NULL values
+	Nullable bool
+	// When Nullable, NULLs are sorted before the other values when true, after when false
+	NullsFirst bool
+	// Converts the values from/to their cursor representation, to preserve their type (ex: TimeCodec)
+	Codec Codec
+	// Set to true if the column holds time values, allows the Dialect to compare them correctly
+	Time bool
+	// Set to true if the values of the column are unique (ex: primary key), no tiebreak column is needed
+	// after it, see AppendPrimaryKey
+	Unique bool
+
+	// Overrides the key of the column, see AppendPrimaryKey
+	cursorKey string
+}
+
+// Computes a version of the columns definition, meant to be used as cursor.EnvelopeOptions.Version
+// so that the cursors issued for a different definition are rejected instead of being misinterpreted.
+// primaryKey is the one appended to the columns by the driver (see AppendPrimaryKey), if any
+func ColumnsVersion(columns []Column, primaryKey ...Column) uint64 {
+	h := fnv.New64a()
+	for _, c := range AppendPrimaryKey(columns, primaryKey) {
+		if c.cursorKey != "" {
+			fmt.Fprintf(h, "%v:", c.cursorKey)
+		}
+		fmt.Fprintf(h, "%v,%v,%v,%v;", c.Name, c.Desc, c.Nullable, c.NullsFirst)
+	}
+
+	return h.Sum64()
 }
 
 func (c Column) Order(t cursor.Type) Order {
@@ -27,3 +60,107 @@ func (c Column) Order(t cursor.Type) Order {
 
 	return order
 }
+
+// Order of the `column IS NULL` expression, used to sort NULLs consistently across databases
+func (c Column) NullsOrder(t cursor.Type) Order {
+	order := OrderAsc
+	if c.NullsFirst {
+		order = OrderDesc
+	}
+
+	if t == cursor.Before {
+		return order.Invert()
+	}
+
+	return order
+}
+
+// Generates the `column op value` condition, taking NULLs into account when the column is Nullable
+func (c Column) compare(op Op, v interface{}) (string, []interface{}) {
+	col, vars := c.Reference(c)
+
+	if !c.Nullable {
+		args := make([]interface{}, 0)
+		args = append(args, vars...)
+		args = append(args, v)
+
+		return fmt.Sprintf("%v %v %v", col, op, c.Placeholder(c)), args
+	}
+
+	// NULLs are considered greater than any value when they are sorted first in a
+	// descending order, or last in an ascending order, smaller otherwise
+	nullsGreater := c.NullsFirst == c.Desc
+
+	// Whether op is looking at values towards the NULLs
+	towardsNulls := op == OpGt || op == OpGte
+	if !nullsGreater {
+		towardsNulls = !towardsNulls
+	}
+
+	if v == nil {
+		switch {
+		case towardsNulls && op.IsInclusive():
+			return fmt.Sprintf("%v IS NULL", col), vars
+		case towardsNulls:
+			return "1=0", nil
+		case op.IsInclusive():
+			return "1=1", nil
+		default:
+			return fmt.Sprintf("%v IS NOT NULL", col), vars
+		}
+	}
+
+	args := make([]interface{}, 0)
+	args = append(args, vars...)
+	args = append(args, v)
+
+	if towardsNulls {
+		args = append(args, vars...)
+
+		return fmt.Sprintf("(%v %v %v OR %v IS NULL)", col, op, c.Placeholder(c), col), args
+	}
+
+	return fmt.Sprintf("%v %v %v", col, op, c.Placeholder(c)), args
+}
+
+// Converts a value read from the database to its cursor representation
+func (c Column) encodeValue(v interface{}) (interface{}, error) {
+	// Unwraps sql.NullX and other driver specific types
+	if valuer, ok := v.(driver.Valuer); ok {
+		var err error
+		v, err = valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if v == nil || c.Codec == nil {
+		return v, nil
+	}
+
+	return c.Codec.Encode(v)
+}
+
+func (c Column) decodeValue(v interface{}) (interface{}, error) {
+	if v == nil || c.Codec == nil {
+		return v, nil
+	}
+
+	return c.Codec.Decode(v)
+}
+
+// Key of the column in the rows and the cursor values, the name without the dots of a qualified name
+// (ex: `orders.created_at` is selected as `orders__created_at`)
+func (c Column) key() string {
+	if c.cursorKey != "" {
+		return c.cursorKey
+	}
+
+	return strings.ReplaceAll(c.Name, ".", "__")
+}
+
+func (c Column) wrap(f func(string) string) Column {
+	c.Name = f(c.Name)
+
+	return c
+}
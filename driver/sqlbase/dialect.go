@@ -0,0 +1,140 @@
+package sqlbase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Defaults of the columns specific to a database, see ResolveColumnsDialect
+type Dialect struct {
+	Name string
+	// Quotes an identifier, used by the drivers which do not quote the columns themselves (ex: sql)
+	Quote func(name string) string
+	// Wraps the placeholders and the references of the time columns (see Column.Time) so that they
+	// are compared as time values, ex: `datetime(%v)`
+	TimeFunc string
+	// Rewrites the `?` placeholders of a statement to the bind vars of the database (ex: `$1`), used by the drivers
+	// which do not rebind the statements themselves (ex: sql)
+	Rebind func(query string) string
+}
+
+var (
+	// SQLite stores the times as text, which only compare correctly once normalized by `datetime()`
+	DialectSQLite = Dialect{
+		Name:     "sqlite",
+		Quote:    quoteWith(`"`),
+		TimeFunc: "datetime(%v)",
+	}
+	DialectPostgres = Dialect{
+		Name:   "postgres",
+		Quote:  quoteWith(`"`),
+		Rebind: RebindDollar,
+	}
+	DialectMySQL = Dialect{
+		Name:  "mysql",
+		Quote: quoteWith("`"),
+	}
+)
+
+// Returns the dialect of the database driver name (ex: gorm.Dialector.Name(), or the name passed to sql.Open)
+func DialectFor(name string) (Dialect, bool) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return DialectSQLite, true
+	case "postgres", "postgresql", "pgx":
+		return DialectPostgres, true
+	case "mysql":
+		return DialectMySQL, true
+	}
+
+	return Dialect{}, false
+}
+
+func quoteWith(q string) func(name string) string {
+	return func(name string) string {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = q + strings.ReplaceAll(part, q, q+q) + q
+		}
+
+		return strings.Join(parts, ".")
+	}
+}
+
+// Replaces the `?` placeholders with `$1`, `$2`..., the ones in string literals and quoted identifiers are left untouched
+func RebindDollar(query string) string {
+	var sb strings.Builder
+
+	eachPlaceholder(query, func(r rune, placeholder int) {
+		if placeholder > 0 {
+			fmt.Fprintf(&sb, "$%v", placeholder)
+			return
+		}
+
+		sb.WriteRune(r)
+	})
+
+	return sb.String()
+}
+
+// Counts the `?` placeholders of a statement, the ones in string literals and quoted identifiers excluded
+func countPlaceholders(query string) int {
+	n := 0
+	eachPlaceholder(query, func(_ rune, placeholder int) {
+		if placeholder > 0 {
+			n = placeholder
+		}
+	})
+
+	return n
+}
+
+// Calls f with each rune of query, along with the position of the placeholder (starting at 1) when it is one, 0 otherwise
+func eachPlaceholder(query string, f func(r rune, placeholder int)) {
+	n := 0
+	var quote rune
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			f(r, n)
+			continue
+		}
+
+		f(r, 0)
+	}
+}
+
+// Returns a copy of the columns with the defaults of the dialect applied:
+// the time columns are wrapped with Dialect.TimeFunc, unless they define their own Reference and Placeholder
+func ResolveColumnsDialect(columns []Column, dialect Dialect) []Column {
+	resolved := make([]Column, len(columns))
+	copy(resolved, columns)
+
+	for i := 0; i < len(resolved); i++ {
+		wrap := "%v"
+		if resolved[i].Time && dialect.TimeFunc != "" {
+			wrap = dialect.TimeFunc
+		}
+
+		if resolved[i].Reference == nil {
+			resolved[i].Reference = func(column Column) (string, []interface{}) {
+				return fmt.Sprintf(wrap, column.Name), nil
+			}
+		}
+
+		if resolved[i].Placeholder == nil {
+			resolved[i].Placeholder = func(Column) string {
+				return fmt.Sprintf(wrap, "?")
+			}
+		}
+	}
+
+	return resolved
+}
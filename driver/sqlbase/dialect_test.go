@@ -0,0 +1,57 @@
+package sqlbase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRebindDollar(t *testing.T) {
+	assert.Equal(t, "a > $1 AND (b = $2 OR c = '?') AND \"d?\" = $3", RebindDollar("a > ? AND (b = ? OR c = '?') AND \"d?\" = ?"))
+	assert.Equal(t, "a = 'it''s?' AND b = $1", RebindDollar("a = 'it''s?' AND b = ?"))
+}
+
+func TestDialectFor(t *testing.T) {
+	d, ok := DialectFor("sqlite3")
+	assert.True(t, ok)
+	assert.Equal(t, "sqlite", d.Name)
+	assert.Equal(t, `"users"."created_at"`, d.Quote("users.created_at"))
+
+	d, ok = DialectFor("mysql")
+	assert.True(t, ok)
+	assert.Equal(t, "`na``me`", d.Quote("na`me"))
+
+	_, ok = DialectFor("sqlserver")
+	assert.False(t, ok)
+}
+
+func TestResolveColumnsDialect(t *testing.T) {
+	columns := ResolveColumnsDialect([]Column{
+		{Name: "created_at", Time: true},
+		{Name: "id"},
+		{
+			Name: "updated_at",
+			Time: true,
+			Placeholder: func(Column) string {
+				return "?::timestamp"
+			},
+		},
+	}, DialectSQLite)
+
+	ref := func(c Column) string {
+		r, _ := c.Reference(c)
+		return r
+	}
+
+	assert.Equal(t, "datetime(created_at)", ref(columns[0]))
+	assert.Equal(t, "datetime(?)", columns[0].Placeholder(columns[0]))
+	assert.Equal(t, "id", ref(columns[1]))
+	assert.Equal(t, "?", columns[1].Placeholder(columns[1]))
+	assert.Equal(t, "datetime(updated_at)", ref(columns[2]))
+	assert.Equal(t, "?::timestamp", columns[2].Placeholder(columns[2]))
+
+	columns = ResolveColumnsDialect(columns[:1], DialectPostgres)
+	assert.Equal(t, "datetime(created_at)", ref(columns[0]), "already resolved")
+
+	columns = ResolveColumns([]Column{{Name: "created_at", Time: true}})
+	assert.Equal(t, "created_at", ref(columns[0]))
+}
@@ -1,6 +1,7 @@
 package sqlbase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/raphaelvigee/go-paginate/cursor"
@@ -10,22 +11,44 @@ import (
 	"strings"
 )
 
+// Can optionally implement driver.TotalCounter, SingleQueryExecutor and IntoExecutor
 type Executor interface {
 	WrapColumn(c string) string
-	TakeFirst() (map[string]interface{}, error)
-	CountPrevious(where string, args []interface{}) (int64, error)
-	FindNext(query string, args []interface{}, limit int) ([]map[string]interface{}, error)
+	TakeFirst(ctx context.Context) (map[string]interface{}, error)
+	CountPrevious(ctx context.Context, where string, args []interface{}) (int64, error)
+	FindNext(ctx context.Context, query string, args []interface{}, limit int) ([]map[string]interface{}, error)
 
 	Page(query string, args []interface{}, limit int) driver.Executor
 }
 
 type ExecutorFactoryArgs struct {
 	base.ExecutorFactoryArgs
+	// Columns of the Options, with the primary key appended and the defaults of the Dialect applied
+	Columns []Column
+	Dialect Dialect
 }
 
 type Options struct {
 	Columns         []Column
 	ExecutorFactory func(args ExecutorFactoryArgs) Executor
+	// Generates row value comparisons (ex: `(a, b) > (?, ?)`) instead of the nested
+	// conditions when all the columns share the same direction and are not nullable.
+	// Allows the database to use a composite index as a single range scan, requires
+	// the database to support row values (ex: PostgreSQL, MySQL 8, SQLite 3.15)
+	RowValues bool
+	// See base.Driver
+	NaturalOrder bool
+	// Fetches the rows of the page and checks for the previous ones (through an EXISTS subquery) in a single
+	// statement, instead of separate queries. Requires the Executor to implement SingleQueryExecutor
+	SingleQuery bool
+	// Chooses the defaults of the columns, see ResolveColumnsDialect
+	Dialect Dialect
+	// Returns the dialect of the input (ex: detected from its connection), overrides Dialect
+	DialectFunc func(input interface{}) Dialect
+	// Appended to the columns as the final tiebreak when they are not guaranteed to be unique, see AppendPrimaryKey
+	PrimaryKey []Column
+	// Returns the primary key of the input (ex: from its model), overrides PrimaryKey
+	PrimaryKeyFunc func(input interface{}) []Column
 }
 
 type cursorEncoder struct {
@@ -39,7 +62,12 @@ func (d cursorEncoder) CursorEncode(input interface{}) (interface{}, error) {
 
 		values := make([]interface{}, len(d.Columns))
 		for i, column := range d.Columns {
-			values[i] = s.MapIndex(reflect.ValueOf(column.Name)).Interface()
+			v, err := column.encodeValue(s.MapIndex(reflect.ValueOf(column.key())).Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = v
 		}
 
 		return values, nil
@@ -57,86 +85,217 @@ func (d cursorEncoder) CursorDecode(input interface{}) (interface{}, error) {
 	case reflect.Slice, reflect.Array:
 		s := reflect.ValueOf(input)
 
+		// Values of the columns, followed by the ones of the primary key, see AppendPrimaryKey.
+		// Those depend on the input, they are decoded by the executor, see decodeTiebreak
+		if s.Len() < len(d.Columns) {
+			return nil, &driver.Error{
+				Kind: driver.ErrCursorColumnMismatch,
+				Err:  fmt.Errorf("sqlbase: cursor: decode: %v values for %v columns", s.Len(), len(d.Columns)),
+			}
+		}
+
 		values := make(map[string]interface{}, 0)
 		for i, column := range d.Columns {
-			values[column.Name] = s.Index(i).Interface()
+			v, err := column.decodeValue(s.Index(i).Interface())
+			if err != nil {
+				return nil, driver.InvalidCursor(err)
+			}
+
+			values[column.key()] = v
+		}
+
+		for i := len(d.Columns); i < s.Len(); i++ {
+			values[tiebreakKey(i-len(d.Columns))] = s.Index(i).Interface()
 		}
 
 		return values, nil
 	default:
-		return "", errors.New("gorm: cursor: decode: only slice/array are handled")
+		return "", driver.InvalidCursor(errors.New("gorm: cursor: decode: only slice/array are handled"))
 	}
 }
 
-func New(o Options) driver.Driver {
-	for i := 0; i < len(o.Columns); i++ {
-		if o.Columns[i].Reference == nil {
-			o.Columns[i].Reference = func(column Column) (string, []interface{}) {
-				return column.Name, nil
-			}
-		}
-
-		if o.Columns[i].Placeholder == nil {
-			o.Columns[i].Placeholder = func(Column) string {
-				return "?"
-			}
-		}
-	}
+// Returns a copy of the columns with the defaults applied
+func ResolveColumns(columns []Column) []Column {
+	return ResolveColumnsDialect(columns, Dialect{})
+}
 
+func New(o Options) driver.Driver {
 	return base.Driver{
 		CursorEncoder: cursorEncoder{
 			o.Columns,
 		},
 		ExecutorFactory: func(args base.ExecutorFactoryArgs) base.Executor {
-			return sqlExecutor{
+			dialect := o.Dialect
+			if o.DialectFunc != nil {
+				dialect = o.DialectFunc(args.Input)
+			}
+
+			primaryKey := o.PrimaryKey
+			if o.PrimaryKeyFunc != nil {
+				primaryKey = o.PrimaryKeyFunc(args.Input)
+			}
+
+			columns := ResolveColumnsDialect(AppendPrimaryKey(o.Columns, primaryKey), dialect)
+			value, err := decodeTiebreak(args.Cursor.Value, columns)
+
+			e := sqlExecutor{
 				ExecutorFactoryArgs: args,
-				executor:            o.ExecutorFactory(ExecutorFactoryArgs{args}),
-				columns:             o.Columns,
+				executor:            o.ExecutorFactory(ExecutorFactoryArgs{args, columns, dialect}),
+				columns:             columns,
+				rowValues:           o.RowValues && canCompareRowValues(columns),
 				pop:                 OpLt,
 				nop:                 OpGt,
+				value:               value,
+				err:                 err,
+			}
+
+			if _, ok := e.executor.(SingleQueryExecutor); ok && o.SingleQuery {
+				return singleQueryExecutor{e}
 			}
+
+			return e
 		},
+		NaturalOrder: o.NaturalOrder,
 	}
 }
 
 type sqlExecutor struct {
 	base.ExecutorFactoryArgs
-	executor Executor
-	columns  []Column
+	executor  Executor
+	columns   []Column
+	rowValues bool
+	// Value of the cursor, the values of the primary key decoded by its columns, see decodeTiebreak
+	value map[string]interface{}
+	// See cursor.Cursor.Until
+	until map[string]interface{}
+	// Returned instead of querying, the cursor does not match the columns, see decodeTiebreak
+	err error
 
 	pop Op
 	nop Op
 }
 
 var _ base.Executor = (*sqlExecutor)(nil)
+var _ base.UntilExecutor = (*sqlExecutor)(nil)
+var _ base.IntoExecutor = (*sqlExecutor)(nil)
+var _ base.CursorEncodeExecutor = (*sqlExecutor)(nil)
+
+func (e sqlExecutor) Until(until interface{}) base.Executor {
+	var err error
+	e.until, err = decodeTiebreak(until, e.columns)
+	if e.err == nil {
+		e.err = err
+	}
+
+	return e
+}
+
+// Encodes the values of the columns along with the ones of the primary key appended to them, see AppendPrimaryKey
+func (e sqlExecutor) CursorEncode(input interface{}) (interface{}, error) {
+	return cursorEncoder{e.columns}.CursorEncode(input)
+}
+
+func (e sqlExecutor) TakeFirst(ctx context.Context) (interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if e.until == nil {
+		return e.executor.TakeFirst(ctx)
+	}
+
+	uq, uargs := e.GenerateCondition(e.Cursor.Type, e.until, e.pop)
+	values, err := e.executor.FindNext(ctx, uq, uargs, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, base.ErrNoResult
+	}
+
+	return values[0], nil
+}
+
+func (e sqlExecutor) TotalCount(ctx context.Context) (int64, error) {
+	tc, ok := e.executor.(driver.TotalCounter)
+	if !ok {
+		return 0, driver.ErrTotalCountUnsupported
+	}
 
-func (e sqlExecutor) TakeFirst() (interface{}, error) {
-	return e.executor.TakeFirst()
+	return tc.TotalCount(ctx)
 }
 
-func (e sqlExecutor) CountPrevious(cvalue interface{}) (int64, error) {
-	pq, pargs := e.GenerateCondition(e.Cursor.Type, cvalue.(map[string]interface{}), e.pop)
+// cvalue is the value of the cursor, see value
+func (e sqlExecutor) CountPrevious(ctx context.Context, _ interface{}) (int64, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	pq, pargs := e.GenerateCondition(e.Cursor.Type, e.value, e.pop.Inclusive())
 
-	return e.executor.CountPrevious(pq, pargs)
+	return e.executor.CountPrevious(ctx, pq, pargs)
 }
 
-func (e sqlExecutor) FindNext(cvalue interface{}, isFirst bool) ([]interface{}, error) {
+func (e sqlExecutor) FindNext(ctx context.Context, cvalue interface{}, isFirst bool) ([]interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	// cvalue is the first row when isFirst, the value of the cursor otherwise (see value)
+	m := e.value
 	if isFirst {
 		e.nop = e.nop.Inclusive()
+		m = cvalue.(map[string]interface{})
 	}
 
-	nq, nargs := e.GenerateCondition(e.Cursor.Type, cvalue.(map[string]interface{}), e.nop)
-	nvalues, err := e.executor.FindNext(nq, nargs, e.Cursor.Limit+1)
+	nq, nargs := e.nextCondition(m, e.nop)
+
+	var nvalues []map[string]interface{}
+	var err error
+	if e.ScansInto() {
+		nvalues, err = e.findNextInto(ctx, nq, nargs, "", nil, e.Cursor.Limit+1)
+	} else {
+		nvalues, err = e.executor.FindNext(ctx, nq, nargs, e.Cursor.Limit+1)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	arr := make([]interface{}, len(nvalues))
-	for i := range nvalues {
-		arr[i] = nvalues[i]
+	return toInterfaces(nvalues), nil
+}
+
+// Condition of the rows following cvalue (all the rows when nil), before until if set
+func (e sqlExecutor) nextCondition(cvalue map[string]interface{}, op Op) (string, []interface{}) {
+	conds := make([]string, 0, 2)
+	args := make([]interface{}, 0)
+
+	if cvalue != nil {
+		nq, nargs := e.GenerateCondition(e.Cursor.Type, cvalue, op)
+		conds = append(conds, nq)
+		args = append(args, nargs...)
+	}
+
+	if e.until != nil {
+		uq, uargs := e.GenerateCondition(e.Cursor.Type, e.until, e.pop)
+		conds = append(conds, uq)
+		args = append(args, uargs...)
+	}
+
+	if len(conds) < 2 {
+		return strings.Join(conds, ""), args
 	}
 
-	return arr, nil
+	return fmt.Sprintf("(%v)", strings.Join(conds, " AND ")), args
+}
+
+func toInterfaces(values []map[string]interface{}) []interface{} {
+	arr := make([]interface{}, len(values))
+	for i := range values {
+		arr[i] = values[i]
+	}
+
+	return arr
 }
 
 func (e sqlExecutor) Page(sm, em interface{}) driver.Executor {
@@ -148,6 +307,10 @@ func (e sqlExecutor) Page(sm, em interface{}) driver.Executor {
 }
 
 func (e sqlExecutor) GenerateCondition(typ cursor.Type, values map[string]interface{}, op Op) (string, []interface{}) {
+	if e.rowValues {
+		return e.generateRowValuesCondition(typ, values, op)
+	}
+
 	s := "@@@previous@@@"
 	origS := s
 
@@ -160,26 +323,26 @@ func (e sqlExecutor) GenerateCondition(typ cursor.Type, values map[string]interf
 			cop = cop.Opposite()
 		}
 
-		wc := Column{
-			Name:        e.executor.WrapColumn(column.Name),
-			Desc:        column.Desc,
-			Reference:   column.Reference,
-			Placeholder: column.Placeholder,
+		wc := column.wrap(e.executor.WrapColumn)
+
+		v := values[column.key()]
+
+		// Only the last column carries the inclusiveness of op
+		eop := cop.Exclusive()
+		if i == len(e.columns)-1 {
+			eop = cop
 		}
 
-		c, vars := wc.Reference(wc)
-		v := values[column.Name]
-		vp := wc.Placeholder(wc)
+		ic, iargs := wc.compare(cop.Inclusive(), v)
+		c, cargs := wc.compare(eop, v)
 
 		// https://stackoverflow.com/a/38017813
 		// col op ? AND (col op ? OR (previous))
-		s = fmt.Sprintf("(%v %v %v AND (%v %v %v OR (%s)))", c, cop.Inclusive(), vp, c, cop, vp, s)
+		s = fmt.Sprintf("(%v AND (%v OR (%s)))", ic, c, s)
 
 		args := make([]interface{}, 0)
-		args = append(args, vars...) // c
-		args = append(args, v)       // vp
-		args = append(args, vars...) // c
-		args = append(args, v)       // vp
+		args = append(args, iargs...)
+		args = append(args, cargs...)
 
 		copy(argsa[i*2:], [][]interface{}{args})
 	}
@@ -193,3 +356,43 @@ func (e sqlExecutor) GenerateCondition(typ cursor.Type, values map[string]interf
 
 	return s, args
 }
+
+// (col1, col2) op (?, ?)
+func (e sqlExecutor) generateRowValuesCondition(typ cursor.Type, values map[string]interface{}, op Op) (string, []interface{}) {
+	if e.columns[0].Order(typ) == OrderDesc {
+		op = op.Opposite()
+	}
+
+	cs := make([]string, len(e.columns))
+	vps := make([]string, len(e.columns))
+	cargs := make([]interface{}, 0)
+	vargs := make([]interface{}, 0)
+	for i, column := range e.columns {
+		wc := column.wrap(e.executor.WrapColumn)
+
+		c, vars := wc.Reference(wc)
+		cs[i] = c
+		cargs = append(cargs, vars...)
+
+		vps[i] = wc.Placeholder(wc)
+		vargs = append(vargs, values[column.key()])
+	}
+
+	s := fmt.Sprintf("(%v) %v (%v)", strings.Join(cs, ", "), op, strings.Join(vps, ", "))
+
+	return s, append(cargs, vargs...)
+}
+
+func canCompareRowValues(columns []Column) bool {
+	if len(columns) == 0 {
+		return false
+	}
+
+	for _, column := range columns {
+		if column.Nullable || column.Desc != columns[0].Desc {
+			return false
+		}
+	}
+
+	return true
+}
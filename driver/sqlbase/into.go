@@ -0,0 +1,55 @@
+package sqlbase
+
+import (
+	"context"
+	"strings"
+)
+
+// Prefix of the aliases of the columns selected along with the rows, see SelectAliased
+const ColumnAliasPrefix = "paginate_cursor_"
+
+// Can be implemented by the Executor to support base.Driver.PaginateInto
+type IntoExecutor interface {
+	// Returns whether the rows can be scanned into dst (ex: depending on its type)
+	CanScanInto(dst interface{}) bool
+	// Same as FindNext (or FindNextWithPrevious when previous is not empty), but selects the rows along with
+	// the columns (see SelectAliased), scans the rows into dst and returns the values of the aliased columns
+	FindNextInto(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int, dst interface{}) ([]map[string]interface{}, error)
+}
+
+func ColumnAlias(column Column) string {
+	return ColumnAliasPrefix + column.key()
+}
+
+// Returns whether name is one of the aliases added to the rows by the driver,
+// allows to remove them from the rows scanned into a map
+func IsAlias(name string) bool {
+	return name == HasPreviousAlias || strings.HasPrefix(name, ColumnAliasPrefix)
+}
+
+func (e sqlExecutor) ScansInto() bool {
+	ie, ok := e.executor.(IntoExecutor)
+
+	return ok && e.Dst != nil && ie.CanScanInto(e.Dst)
+}
+
+func (e sqlExecutor) findNextInto(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int) ([]map[string]interface{}, error) {
+	rows, err := e.executor.(IntoExecutor).FindNextInto(ctx, query, args, previous, previousArgs, limit, e.Dst)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make(map[string]interface{}, len(e.columns))
+		for _, column := range e.columns {
+			values[i][column.key()] = row[ColumnAlias(column)]
+		}
+
+		if hp, ok := row[HasPreviousAlias]; ok {
+			values[i][HasPreviousAlias] = hp
+		}
+	}
+
+	return values, nil
+}
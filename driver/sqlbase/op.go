@@ -14,6 +14,11 @@ var inclusive = map[Op]Op{
 	OpGt: OpGte,
 }
 
+var exclusive = map[Op]Op{
+	OpLte: OpLt,
+	OpGte: OpGt,
+}
+
 func (o Op) IsInclusive() bool {
 	switch o {
 	case OpLte, OpGte:
@@ -31,6 +36,14 @@ func (o Op) Inclusive() Op {
 	return inclusive[o]
 }
 
+func (o Op) Exclusive() Op {
+	if !o.IsInclusive() {
+		return o
+	}
+
+	return exclusive[o]
+}
+
 func (o Op) Opposite() Op {
 	switch o {
 	case OpLt:
@@ -0,0 +1,103 @@
+package sqlbase
+
+import (
+	"context"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"reflect"
+	"strings"
+)
+
+// Alias of the column carrying the result of the previous rows check, see SingleQueryExecutor
+const HasPreviousAlias = "paginate_has_previous"
+
+// Can be implemented by the Executor to support Options.SingleQuery
+type SingleQueryExecutor interface {
+	// Same as FindNext (all the rows when query is empty), when previous is not empty, the rows must
+	// also carry whether any row matches previous as a HasPreviousAlias column (ex: `EXISTS (...) AS paginate_has_previous`)
+	FindNextWithPrevious(ctx context.Context, query string, args []interface{}, previous string, previousArgs []interface{}, limit int) ([]map[string]interface{}, error)
+}
+
+// Replaces TakeFirst, CountPrevious and FindNext by a single statement, see Options.SingleQuery
+type singleQueryExecutor struct {
+	sqlExecutor
+}
+
+var _ base.PageFinder = (*singleQueryExecutor)(nil)
+var _ base.UntilExecutor = (*singleQueryExecutor)(nil)
+
+func (e singleQueryExecutor) Until(until interface{}) base.Executor {
+	return singleQueryExecutor{e.sqlExecutor.Until(until).(sqlExecutor)}
+}
+
+func (e singleQueryExecutor) FindPage(ctx context.Context, cvalue interface{}) ([]interface{}, bool, error) {
+	if e.err != nil {
+		return nil, false, e.err
+	}
+
+	// cvalue is the value of the cursor, see value
+	var previous string
+	var previousArgs []interface{}
+	if cvalue != nil {
+		previous, previousArgs = e.GenerateCondition(e.Cursor.Type, e.value, e.pop.Inclusive())
+	}
+
+	nq, nargs := e.nextCondition(e.value, e.nop)
+
+	var nvalues []map[string]interface{}
+	var err error
+	if e.ScansInto() {
+		nvalues, err = e.findNextInto(ctx, nq, nargs, previous, previousArgs, e.Cursor.Limit+1)
+	} else {
+		nvalues, err = e.executor.(SingleQueryExecutor).FindNextWithPrevious(ctx, nq, nargs, previous, previousArgs, e.Cursor.Limit+1)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasPrevious := false
+	for _, v := range nvalues {
+		hasPrevious = hasPrevious || isTrue(v[HasPreviousAlias])
+		delete(v, HasPreviousAlias)
+	}
+
+	// Without any row, the result of the check could not be carried
+	if len(nvalues) == 0 && cvalue != nil {
+		pc, err := e.CountPrevious(ctx, cvalue)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hasPrevious = pc > 0
+	}
+
+	return toInterfaces(nvalues), hasPrevious, nil
+}
+
+// Interprets the boolean returned by the database, depending on the driver: bool, integer or text
+func isTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case []byte:
+		return isTrue(string(v))
+	case string:
+		switch strings.ToLower(v) {
+		case "1", "t", "true":
+			return true
+		}
+
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint() != 0
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package sqlbase
+
+import (
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/driver"
+)
+
+// Key of the i-th column of the primary key appended to the columns, see AppendPrimaryKey
+func tiebreakKey(i int) string {
+	return fmt.Sprintf("paginate_tiebreak_%v", i)
+}
+
+// Appends the columns of the primary key to the columns, as the final tiebreak, unless the columns are already
+// guaranteed to be unique: one of them is Unique (and not Nullable), or they contain the whole primary key.
+// The appended columns follow the direction of the last column, and their values are carried by the cursor
+// after the values of the columns, so that the primary key can depend on the input
+func AppendPrimaryKey(columns []Column, primaryKey []Column) []Column {
+	if len(primaryKey) == 0 || isUnique(columns, primaryKey) {
+		return columns
+	}
+
+	desc := false
+	if len(columns) > 0 {
+		desc = columns[len(columns)-1].Desc
+	}
+
+	appended := make([]Column, len(columns), len(columns)+len(primaryKey))
+	copy(appended, columns)
+
+	for i, column := range primaryKey {
+		column.Desc = desc
+		column.Nullable = false
+		column.NullsFirst = false
+		column.cursorKey = tiebreakKey(i)
+
+		appended = append(appended, column)
+	}
+
+	return appended
+}
+
+// Checks that the decoded cursor values carry as many values of the primary key as appended to the columns
+// (the cursors issued before the primary key was appended, or for another one, would skip rows otherwise),
+// and returns a copy of the values, the ones of the primary key decoded by their columns (see Column.Codec)
+func decodeTiebreak(value interface{}, columns []Column) (map[string]interface{}, error) {
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	expected := 0
+	for _, column := range columns {
+		if column.cursorKey != "" {
+			expected++
+		}
+	}
+
+	n := 0
+	for _, ok := values[tiebreakKey(n)]; ok; _, ok = values[tiebreakKey(n)] {
+		n++
+	}
+
+	if n != expected {
+		return nil, &driver.Error{
+			Kind: driver.ErrCursorColumnMismatch,
+			Err:  fmt.Errorf("sqlbase: cursor: %v primary key values for %v columns", n, expected),
+		}
+	}
+
+	decoded := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		decoded[k] = v
+	}
+
+	for _, column := range columns {
+		if column.cursorKey == "" {
+			continue
+		}
+
+		v, err := column.decodeValue(values[column.cursorKey])
+		if err != nil {
+			return nil, driver.InvalidCursor(err)
+		}
+
+		decoded[column.cursorKey] = v
+	}
+
+	return decoded, nil
+}
+
+func isUnique(columns []Column, primaryKey []Column) bool {
+	names := map[string]bool{}
+	for _, column := range columns {
+		if column.Unique && !column.Nullable {
+			return true
+		}
+
+		names[column.Name] = true
+	}
+
+	for _, column := range primaryKey {
+		if !names[column.Name] {
+			return false
+		}
+	}
+
+	return true
+}
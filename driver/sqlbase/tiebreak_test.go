@@ -0,0 +1,126 @@
+package sqlbase
+
+import (
+	"errors"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAppendPrimaryKey(t *testing.T) {
+	pk := []Column{{Name: "id"}}
+
+	columns := AppendPrimaryKey([]Column{{Name: "created_at", Desc: true}}, pk)
+	require.Len(t, columns, 2)
+	assert.Equal(t, "id", columns[1].Name)
+	assert.True(t, columns[1].Desc, "follows the last column")
+	assert.Equal(t, "paginate_tiebreak_0", columns[1].key())
+
+	for name, columns := range map[string][]Column{
+		"unique":      {{Name: "email", Unique: true}},
+		"contains pk": {{Name: "created_at"}, {Name: "id"}},
+	} {
+		assert.Equal(t, columns, AppendPrimaryKey(columns, pk), name)
+	}
+
+	columns = AppendPrimaryKey([]Column{{Name: "email", Unique: true, Nullable: true}}, pk)
+	assert.Len(t, columns, 2, "NULLs are not unique")
+
+	columns = AppendPrimaryKey([]Column{{Name: "created_at"}}, nil)
+	assert.Len(t, columns, 1)
+}
+
+func TestAppendPrimaryKey_Cursor(t *testing.T) {
+	columns := []Column{{Name: "created_at"}}
+	appended := AppendPrimaryKey(columns, []Column{{Name: "a"}, {Name: "b", Codec: TimeCodec()}})
+	e := cursorEncoder{columns}
+
+	b := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	encoded, err := cursorEncoder{appended}.CursorEncode(map[string]interface{}{
+		"created_at":          int64(1),
+		"paginate_tiebreak_0": "a",
+		"paginate_tiebreak_1": b,
+	})
+	require.NoError(t, err)
+	require.Len(t, encoded, 3)
+	assert.Equal(t, []interface{}{int64(1), "a"}, encoded.([]interface{})[:2])
+	assert.IsType(t, []interface{}{}, encoded.([]interface{})[2], "encoded by the codec of the primary key")
+
+	m := cursor.MsgPack()
+	data, err := m.Marshal(encoded)
+	require.NoError(t, err)
+	decoded, err := m.Unmarshal(data)
+	require.NoError(t, err)
+
+	values, err := e.CursorDecode(decoded)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+
+	// The values of the primary key are decoded by the executor, which knows its columns
+	tvalues, err := decodeTiebreak(values, appended)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tvalues["created_at"])
+	assert.Equal(t, "a", tvalues["paginate_tiebreak_0"])
+	assert.True(t, b.Equal(tvalues["paginate_tiebreak_1"].(time.Time)))
+	assert.Equal(t, time.UTC, tvalues["paginate_tiebreak_1"].(time.Time).Location())
+}
+
+func TestCursorDecode_Errors(t *testing.T) {
+	e := cursorEncoder{[]Column{{Name: "created_at"}, {Name: "id"}}}
+
+	_, err := e.CursorDecode([]interface{}{int64(1)})
+	assert.True(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+	assert.True(t, errors.Is(err, driver.ErrInvalidCursor))
+	assert.EqualError(t, err, "invalid cursor: column mismatch: sqlbase: cursor: decode: 1 values for 2 columns")
+
+	_, err = e.CursorDecode("abc")
+	assert.True(t, errors.Is(err, driver.ErrInvalidCursor))
+	assert.False(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+
+	e = cursorEncoder{[]Column{{Name: "created_at", Codec: TimeCodec()}}}
+	_, err = e.CursorDecode([]interface{}{"abc"})
+	assert.True(t, errors.Is(err, driver.ErrInvalidCursor))
+}
+
+func TestDecodeTiebreak(t *testing.T) {
+	columns := AppendPrimaryKey([]Column{{Name: "created_at"}}, []Column{{Name: "id"}})
+
+	values, err := decodeTiebreak(nil, columns)
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+
+	values, err = decodeTiebreak(map[string]interface{}{"created_at": 1, "paginate_tiebreak_0": nil}, columns)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"created_at": 1, "paginate_tiebreak_0": nil}, values)
+
+	_, err = decodeTiebreak(map[string]interface{}{"created_at": 1}, columns)
+	assert.True(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+	assert.EqualError(t, err, "invalid cursor: column mismatch: sqlbase: cursor: 0 primary key values for 1 columns")
+
+	_, err = decodeTiebreak(map[string]interface{}{"created_at": 1, "paginate_tiebreak_0": "a", "paginate_tiebreak_1": "b"}, columns)
+	assert.True(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+
+	_, err = decodeTiebreak(map[string]interface{}{"created_at": 1, "paginate_tiebreak_0": "a"}, columns[:1])
+	assert.True(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+
+	columns = AppendPrimaryKey([]Column{{Name: "created_at"}}, []Column{{Name: "id", Codec: TimeCodec()}})
+	_, err = decodeTiebreak(map[string]interface{}{"created_at": 1, "paginate_tiebreak_0": "a"}, columns)
+	assert.True(t, errors.Is(err, driver.ErrInvalidCursor))
+	assert.False(t, errors.Is(err, driver.ErrCursorColumnMismatch))
+}
+
+func TestColumnsVersion_PrimaryKey(t *testing.T) {
+	columns := []Column{{Name: "created_at"}}
+
+	assert.Equal(t, ColumnsVersion(columns), ColumnsVersion(columns, []Column{}...))
+	assert.NotEqual(t, ColumnsVersion(columns), ColumnsVersion(columns, Column{Name: "id"}))
+	assert.NotEqual(t, ColumnsVersion(append(columns, Column{Name: "id"})), ColumnsVersion(columns, Column{Name: "id"}))
+	assert.NotEqual(t, ColumnsVersion(columns, Column{Name: "id"}), ColumnsVersion(columns, Column{Name: "uuid"}))
+
+	unique := []Column{{Name: "email", Unique: true}}
+	assert.Equal(t, ColumnsVersion(unique), ColumnsVersion(unique, Column{Name: "id"}), "nothing appended")
+}
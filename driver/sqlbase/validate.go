@@ -0,0 +1,98 @@
+package sqlbase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"strings"
+)
+
+var (
+	// Wrapped by all the errors returned by Options.Validate
+	ErrInvalidOptions = errors.New("sqlbase: invalid options")
+	ErrNoColumns      = fmt.Errorf("%w: no column", ErrInvalidOptions)
+)
+
+func invalidOptions(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidOptions}, args...)...)
+}
+
+// Checks the options upfront, misconfigurations would otherwise only surface when paginating
+// (as panics, or errors of the cursor encoding)
+func (o Options) Validate() error {
+	if o.ExecutorFactory == nil {
+		return invalidOptions("no executor factory")
+	}
+
+	if err := ValidateDialect(o.Dialect); err != nil {
+		return err
+	}
+
+	if err := ValidateColumns(o.Columns, o.Dialect); err != nil {
+		return err
+	}
+
+	if len(o.PrimaryKey) > 0 {
+		if err := ValidateColumns(o.PrimaryKey, o.Dialect); err != nil {
+			return fmt.Errorf("primary key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ValidateDialect(dialect Dialect) error {
+	if dialect.TimeFunc != "" && strings.Count(dialect.TimeFunc, "%v") != 1 {
+		return invalidOptions("dialect %q: time func %q must contain %%v once", dialect.Name, dialect.TimeFunc)
+	}
+
+	return nil
+}
+
+// Checks that there is at least one column, that their names (and keys, see Select) are unique,
+// and that their Reference and Placeholder produce as many `?` placeholders as vars
+func ValidateColumns(columns []Column, dialect Dialect) error {
+	if len(columns) == 0 {
+		return ErrNoColumns
+	}
+
+	keys := map[string]string{}
+	for i, column := range ResolveColumnsDialect(columns, dialect) {
+		if column.Name == "" {
+			return invalidOptions("column %v: empty name", i)
+		}
+
+		if other, ok := keys[column.key()]; ok {
+			if other == column.Name {
+				return invalidOptions("duplicate column %q", column.Name)
+			}
+
+			return invalidOptions("columns %q and %q are both selected as %q", other, column.Name, column.key())
+		}
+		keys[column.key()] = column.Name
+
+		if column.NullsFirst && !column.Nullable {
+			return invalidOptions("column %q: NullsFirst requires Nullable", column.Name)
+		}
+
+		ref, vars := column.Reference(column)
+		if n := countPlaceholders(ref); n != len(vars) {
+			return invalidOptions("column %q: reference %q has %v placeholders for %v vars", column.Name, ref, n, len(vars))
+		}
+
+		if placeholder := column.Placeholder(column); countPlaceholders(placeholder) != 1 {
+			return invalidOptions("column %q: placeholder %q must contain one `?`", column.Name, placeholder)
+		}
+	}
+
+	return nil
+}
+
+// Same as New, validating the options first, see Options.Validate
+func NewValidated(o Options) (driver.Driver, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(o), nil
+}
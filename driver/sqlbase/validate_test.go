@@ -0,0 +1,124 @@
+package sqlbase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	factory := func(args ExecutorFactoryArgs) Executor {
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		options Options
+		err     string
+	}{
+		{
+			name:    "no columns",
+			options: Options{ExecutorFactory: factory},
+			err:     "sqlbase: invalid options: no column",
+		},
+		{
+			name:    "no executor factory",
+			options: Options{Columns: []Column{{Name: "id"}}},
+			err:     "sqlbase: invalid options: no executor factory",
+		},
+		{
+			name:    "empty name",
+			options: Options{Columns: []Column{{Name: "id"}, {}}, ExecutorFactory: factory},
+			err:     "sqlbase: invalid options: column 1: empty name",
+		},
+		{
+			name:    "duplicate",
+			options: Options{Columns: []Column{{Name: "id"}, {Name: "id", Desc: true}}, ExecutorFactory: factory},
+			err:     `sqlbase: invalid options: duplicate column "id"`,
+		},
+		{
+			name:    "same key",
+			options: Options{Columns: []Column{{Name: "users.id"}, {Name: "users__id"}}, ExecutorFactory: factory},
+			err:     `sqlbase: invalid options: columns "users.id" and "users__id" are both selected as "users__id"`,
+		},
+		{
+			name:    "nulls first",
+			options: Options{Columns: []Column{{Name: "id", NullsFirst: true}}, ExecutorFactory: factory},
+			err:     `sqlbase: invalid options: column "id": NullsFirst requires Nullable`,
+		},
+		{
+			name: "reference vars",
+			options: Options{
+				Columns: []Column{{
+					Name: "score",
+					Reference: func(column Column) (string, []interface{}) {
+						return "score + ?", nil
+					},
+				}},
+				ExecutorFactory: factory,
+			},
+			err: `sqlbase: invalid options: column "score": reference "score + ?" has 1 placeholders for 0 vars`,
+		},
+		{
+			name: "placeholder",
+			options: Options{
+				Columns: []Column{{
+					Name: "score",
+					Placeholder: func(column Column) string {
+						return "42"
+					},
+				}},
+				ExecutorFactory: factory,
+			},
+			err: `sqlbase: invalid options: column "score": placeholder "42" must contain one ` + "`?`",
+		},
+		{
+			name: "time func",
+			options: Options{
+				Columns:         []Column{{Name: "created_at", Time: true}},
+				Dialect:         Dialect{Name: "custom", TimeFunc: "datetime()"},
+				ExecutorFactory: factory,
+			},
+			err: `sqlbase: invalid options: dialect "custom": time func "datetime()" must contain %v once`,
+		},
+		{
+			name: "primary key",
+			options: Options{
+				Columns:         []Column{{Name: "created_at"}},
+				PrimaryKey:      []Column{{Name: "id"}, {Name: "id"}},
+				ExecutorFactory: factory,
+			},
+			err: `primary key: sqlbase: invalid options: duplicate column "id"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewValidated(test.options)
+			require.Error(t, err)
+			assert.Nil(t, d)
+			assert.Equal(t, test.err, err.Error())
+			assert.True(t, errors.Is(err, ErrInvalidOptions))
+		})
+	}
+
+	d, err := NewValidated(Options{
+		Columns: []Column{
+			{Name: "created_at", Time: true, Nullable: true, NullsFirst: true},
+			{
+				Name: "score",
+				Reference: func(column Column) (string, []interface{}) {
+					return "score * ? + '?'", []interface{}{2}
+				},
+				Placeholder: func(column Column) string {
+					return "CAST(? AS int)"
+				},
+			},
+		},
+		Dialect:         DialectSQLite,
+		ExecutorFactory: factory,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+}
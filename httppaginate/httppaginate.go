@@ -0,0 +1,167 @@
+// Helpers to paginate REST endpoints: reads the cursor from the request query parameters
+// and writes the RFC 8288 Link header and JSON envelope of the response
+package httppaginate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	DirectionAfter  = "after"
+	DirectionBefore = "before"
+)
+
+var (
+	ErrInvalidLimit     = errors.New("httppaginate: invalid limit")
+	ErrInvalidDirection = errors.New("httppaginate: invalid direction")
+)
+
+type Options struct {
+	*go_paginate.Paginator
+
+	// Name of the query parameters, default to "cursor", "direction" and "limit"
+	CursorParam    string
+	DirectionParam string
+	LimitParam     string
+
+	// Used when the limit parameter is missing, defaults to 20 (or MaxLimit if lower)
+	DefaultLimit int
+	// The limit parameter is clamped to this value, unlimited when 0
+	MaxLimit int
+}
+
+func New(o Options) *Paginator {
+	p := &Paginator{Options: o}
+
+	if p.CursorParam == "" {
+		p.CursorParam = "cursor"
+	}
+
+	if p.DirectionParam == "" {
+		p.DirectionParam = "direction"
+	}
+
+	if p.LimitParam == "" {
+		p.LimitParam = "limit"
+	}
+
+	if p.DefaultLimit == 0 {
+		p.DefaultLimit = 20
+	}
+
+	if p.MaxLimit > 0 && p.DefaultLimit > p.MaxLimit {
+		p.DefaultLimit = p.MaxLimit
+	}
+
+	return p
+}
+
+type Paginator struct {
+	Options
+}
+
+// Creates the cursor from the query parameters of the request
+func (p *Paginator) Cursor(r *http.Request) (cursor.Cursor, error) {
+	q := r.URL.Query()
+
+	typ := cursor.After
+	switch d := q.Get(p.DirectionParam); d {
+	case "", DirectionAfter:
+	case DirectionBefore:
+		typ = cursor.Before
+	default:
+		return cursor.Cursor{}, fmt.Errorf("%w: %q", ErrInvalidDirection, d)
+	}
+
+	limit := p.DefaultLimit
+	if l := q.Get(p.LimitParam); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			return cursor.Cursor{}, fmt.Errorf("%w: %q", ErrInvalidLimit, l)
+		}
+	}
+
+	if p.MaxLimit > 0 && limit > p.MaxLimit {
+		limit = p.MaxLimit
+	}
+
+	return p.Paginator.Cursor(q.Get(p.CursorParam), typ, limit)
+}
+
+// Generates the links to the pages around the one obtained with c, as RFC 8288 Link header values.
+// "next" continues in the direction of c, "prev" goes back in the opposite direction
+func (p *Paginator) Links(r *http.Request, c cursor.Cursor, page go_paginate.Page) []string {
+	direction, opposite := DirectionAfter, DirectionBefore
+	if c.Type == cursor.Before {
+		direction, opposite = opposite, direction
+	}
+
+	// PageInfo in the order of the traversal
+	info := page.PageInfo
+	if c.Type == cursor.Before && page.NaturalOrder {
+		info = go_paginate.PageInfo{
+			HasNextPage:     info.HasPreviousPage,
+			HasPreviousPage: info.HasNextPage,
+			StartCursor:     info.EndCursor,
+			EndCursor:       info.StartCursor,
+		}
+	}
+
+	links := make([]string, 0, 2)
+
+	if info.HasNextPage {
+		links = append(links, p.link(r, info.EndCursor, direction, c.Limit, "next"))
+	}
+
+	if info.HasPreviousPage {
+		links = append(links, p.link(r, info.StartCursor, opposite, c.Limit, "prev"))
+	}
+
+	return links
+}
+
+func (p *Paginator) link(r *http.Request, encoded, direction string, limit int, rel string) string {
+	u := *r.URL
+
+	q := u.Query()
+	q.Set(p.CursorParam, encoded)
+	q.Set(p.DirectionParam, direction)
+	q.Set(p.LimitParam, strconv.Itoa(limit))
+	u.RawQuery = q.Encode()
+
+	return fmt.Sprintf("<%v>; rel=%q", u.String(), rel)
+}
+
+// Sets the Link header of the response, see Links
+func (p *Paginator) SetLinkHeader(w http.ResponseWriter, r *http.Request, c cursor.Cursor, page go_paginate.Page) {
+	links := p.Links(r, c, page)
+	if len(links) == 0 {
+		return
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+type Envelope struct {
+	Data     interface{}          `json:"data"`
+	PageInfo go_paginate.PageInfo `json:"pageInfo"`
+}
+
+// Sets the Link header and writes data along with the page info as JSON
+func (p *Paginator) WriteJSON(w http.ResponseWriter, r *http.Request, c cursor.Cursor, page go_paginate.Page, data interface{}) error {
+	p.SetLinkHeader(w, r, c, page)
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(Envelope{
+		Data:     data,
+		PageInfo: page.PageInfo,
+	})
+}
@@ -0,0 +1,224 @@
+package httppaginate
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	sqldriver "github.com/raphaelvigee/go-paginate/driver/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+func setup(t *testing.T) (*sql.DB, *Paginator) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES (1, 'u1'), (2, 'u2'), (3, 'u3'), (4, 'u4'), (5, 'u5')")
+	require.NoError(t, err)
+
+	p := New(Options{
+		Paginator: go_paginate.New(go_paginate.Options{
+			Driver: sqldriver.New(sqldriver.Options{
+				Columns: []sqldriver.Column{{Name: "id"}},
+			}),
+		}),
+		DefaultLimit: 2,
+		MaxLimit:     3,
+	})
+
+	return db, p
+}
+
+func handler(db *sql.DB, p *Paginator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := p.Cursor(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		page, err := p.Paginate(c, sqldriver.Input{DB: db, Table: "users"})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var users []map[string]interface{}
+		if err := page.Query(&users); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		names := make([]interface{}, len(users))
+		for i, u := range users {
+			names[i] = u["name"]
+		}
+
+		if err := p.WriteJSON(w, r, c, page, names); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+var linkRegexp = regexp.MustCompile(`<([^>]+)>; rel="(\w+)"`)
+
+func get(t *testing.T, h http.Handler, target string) (map[string]string, []interface{}) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	links := map[string]string{}
+	for _, m := range linkRegexp.FindAllStringSubmatch(rec.Header().Get("Link"), -1) {
+		links[m[2]] = m[1]
+	}
+
+	var envelope struct {
+		Data []interface{}
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &envelope))
+
+	return links, envelope.Data
+}
+
+func TestPaginator(t *testing.T) {
+	db, p := setup(t)
+	defer db.Close()
+
+	h := handler(db, p)
+
+	links, data := get(t, h, "/users?sort=name")
+	assert.Equal(t, []interface{}{"u1", "u2"}, data)
+	assert.NotContains(t, links, "prev")
+	require.Contains(t, links, "next")
+
+	u, err := url.Parse(links["next"])
+	require.NoError(t, err)
+	assert.Equal(t, "/users", u.Path)
+	assert.Equal(t, "name", u.Query().Get("sort"))
+	assert.Equal(t, "after", u.Query().Get("direction"))
+	assert.Equal(t, "2", u.Query().Get("limit"))
+
+	links, data = get(t, h, links["next"])
+	assert.Equal(t, []interface{}{"u3", "u4"}, data)
+	require.Contains(t, links, "prev")
+	require.Contains(t, links, "next")
+
+	prev := links["prev"]
+
+	links, data = get(t, h, links["next"])
+	assert.Equal(t, []interface{}{"u5"}, data)
+	assert.NotContains(t, links, "next")
+
+	links, data = get(t, h, prev)
+	assert.Equal(t, []interface{}{"u2", "u1"}, data)
+	assert.NotContains(t, links, "next")
+	assert.Contains(t, links, "prev")
+}
+
+func TestPaginator_Cursor(t *testing.T) {
+	db, p := setup(t)
+	defer db.Close()
+
+	tests := []struct {
+		target string
+		typ    cursor.Type
+		limit  int
+		err    error
+	}{
+		{target: "/", typ: cursor.After, limit: 2},
+		{target: "/?limit=1&direction=before", typ: cursor.Before, limit: 1},
+		{target: "/?limit=100", typ: cursor.After, limit: 3},
+		{target: "/?limit=0", err: ErrInvalidLimit},
+		{target: "/?limit=abc", err: ErrInvalidLimit},
+		{target: "/?direction=up", err: ErrInvalidDirection},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			c, err := p.Cursor(httptest.NewRequest(http.MethodGet, test.target, nil))
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.typ, c.Type)
+			assert.Equal(t, test.limit, c.Limit)
+		})
+	}
+}
+
+func TestPaginator_CustomParams(t *testing.T) {
+	p := New(Options{
+		CursorParam:    "c",
+		DirectionParam: "d",
+		LimitParam:     "l",
+		MaxLimit:       5,
+	})
+
+	assert.Equal(t, 5, p.DefaultLimit)
+
+	r := httptest.NewRequest(http.MethodGet, "/?l=4&d=before", nil)
+	links := p.Links(r, cursor.Cursor{Type: cursor.Before, Limit: 4}, go_paginate.Page{
+		PageInfo: go_paginate.PageInfo{
+			HasNextPage: true,
+			EndCursor:   "abc",
+		},
+	})
+
+	assert.Equal(t, []string{`</?c=abc&d=before&l=4>; rel="next"`}, links)
+}
+
+func TestPaginator_NaturalOrder(t *testing.T) {
+	db, _ := setup(t)
+	defer db.Close()
+
+	p := New(Options{
+		Paginator: go_paginate.New(go_paginate.Options{
+			Driver: sqldriver.New(sqldriver.Options{
+				Columns:      []sqldriver.Column{{Name: "id"}},
+				NaturalOrder: true,
+			}),
+		}),
+		DefaultLimit: 2,
+	})
+
+	h := handler(db, p)
+
+	links, data := get(t, h, "/users?direction=before")
+	assert.Equal(t, []interface{}{"u4", "u5"}, data)
+	assert.NotContains(t, links, "prev")
+	require.Contains(t, links, "next")
+
+	u, err := url.Parse(links["next"])
+	require.NoError(t, err)
+	assert.Equal(t, "before", u.Query().Get("direction"))
+
+	links, data = get(t, h, links["next"])
+	assert.Equal(t, []interface{}{"u2", "u3"}, data)
+	require.Contains(t, links, "next")
+	require.Contains(t, links, "prev")
+
+	prev := links["prev"]
+
+	links, data = get(t, h, links["next"])
+	assert.Equal(t, []interface{}{"u1"}, data)
+	assert.NotContains(t, links, "next")
+
+	links, data = get(t, h, prev)
+	assert.Equal(t, []interface{}{"u4", "u5"}, data)
+	assert.NotContains(t, links, "next")
+}
@@ -1,11 +1,21 @@
 package go_paginate
 
 import (
+	"context"
 	"encoding/base64"
 	"github.com/raphaelvigee/go-paginate/cursor"
 	"github.com/raphaelvigee/go-paginate/driver"
 )
 
+// See driver.ErrInvalidCursor and the others: malformed cursors from the clients are ErrInvalidCursor
+// (and ErrCursorColumnMismatch), the errors of the database are returned as is
+var (
+	ErrInvalidCursor        = driver.ErrInvalidCursor
+	ErrCursorColumnMismatch = driver.ErrCursorColumnMismatch
+	ErrLimitOutOfRange      = driver.ErrLimitOutOfRange
+	ErrNoCursor             = driver.ErrNoCursor
+)
+
 type PageInfo struct {
 	HasNextPage     bool   `json:"hasNextPage"`
 	HasPreviousPage bool   `json:"hasPreviousPage"`
@@ -17,7 +27,53 @@ type Page struct {
 	driver.Executor
 	PageInfo
 
+	// The rows and the PageInfo are in the natural order, regardless of the cursor type (see driver.NaturalOrderer).
+	// Otherwise, they are in the order of the traversal: pages of Before cursors are inverted
+	NaturalOrder bool
+
 	CursorFunc func(i int64) (string, error)
+
+	// Context passed to PaginateContext, used by Query and Count
+	ctx context.Context
+}
+
+func (p Page) Query(dst interface{}) error {
+	return p.QueryContext(p.context(), dst)
+}
+
+func (p Page) QueryContext(ctx context.Context, dst interface{}) error {
+	return p.Executor.Query(ctx, dst)
+}
+
+func (p Page) Count() (int64, error) {
+	return p.CountContext(p.context())
+}
+
+func (p Page) CountContext(ctx context.Context) (int64, error) {
+	return p.Executor.Count(ctx)
+}
+
+// Counts all the rows matching the input, regardless of the cursor and limit.
+// Only executed when called, returns driver.ErrTotalCountUnsupported if the driver does not support it
+func (p Page) TotalCount() (int64, error) {
+	return p.TotalCountContext(p.context())
+}
+
+func (p Page) TotalCountContext(ctx context.Context) (int64, error) {
+	tc, ok := p.Executor.(driver.TotalCounter)
+	if !ok {
+		return 0, driver.ErrTotalCountUnsupported
+	}
+
+	return tc.TotalCount(ctx)
+}
+
+func (p Page) context() context.Context {
+	if p.ctx == nil {
+		return context.Background()
+	}
+
+	return p.ctx
 }
 
 func (p Page) Cursor(i int64) (string, error) {
@@ -45,13 +101,10 @@ type Paginator struct {
 	Options
 }
 
+// Decodes the cursor sent by a client, the errors are ErrInvalidCursor, wrapping their cause
+// (ex: cursor.ErrExpired, or the error of the CursorMarshaller)
 func (p *Paginator) Cursor(encoded string, typ cursor.Type, limit int) (cursor.Cursor, error) {
-	data, err := p.CursorMarshaller.Unmarshal([]byte(encoded))
-	if err != nil {
-		return cursor.Cursor{}, err
-	}
-
-	value, err := p.Driver.CursorDecode(data)
+	value, err := p.decode(encoded)
 	if err != nil {
 		return cursor.Cursor{}, err
 	}
@@ -63,14 +116,92 @@ func (p *Paginator) Cursor(encoded string, typ cursor.Type, limit int) (cursor.C
 	}, nil
 }
 
+// Creates a cursor paginating between after and before (both excluded, either can be empty),
+// in the direction of typ: from after towards before for cursor.After, and the other way around for cursor.Before
+func (p *Paginator) WindowCursor(after, before string, typ cursor.Type, limit int) (cursor.Cursor, error) {
+	from, until := after, before
+	if typ == cursor.Before {
+		from, until = before, after
+	}
+
+	c, err := p.Cursor(from, typ, limit)
+	if err != nil {
+		return cursor.Cursor{}, err
+	}
+
+	c.Until, err = p.decode(until)
+	if err != nil {
+		return cursor.Cursor{}, err
+	}
+
+	return c, nil
+}
+
+func (p *Paginator) decode(encoded string) (interface{}, error) {
+	data, err := p.CursorMarshaller.Unmarshal([]byte(encoded))
+	if err != nil {
+		return nil, driver.InvalidCursor(err)
+	}
+
+	value, err := p.Driver.CursorDecode(data)
+	if err != nil {
+		return nil, driver.InvalidCursor(err)
+	}
+
+	return value, nil
+}
+
 func (p *Paginator) Paginate(c cursor.Cursor, input interface{}) (Page, error) {
-	dp, err := p.Driver.Paginate(c, input)
+	return p.PaginateContext(context.Background(), c, input)
+}
+
+func (p *Paginator) PaginateContext(ctx context.Context, c cursor.Cursor, input interface{}) (Page, error) {
+	dp, err := p.Driver.Paginate(ctx, c, input)
 	if err != nil {
 		return Page{}, err
 	}
 
+	return p.page(ctx, dp)
+}
+
+// Paginates and scans the rows of the page into dst (a pointer to a slice), in the same order as Page.Query would.
+// When the driver supports it (see driver.IntoPaginator), the rows are scanned from the statement
+// that finds the cursors, instead of being queried again
+func (p *Paginator) PaginateInto(c cursor.Cursor, input interface{}, dst interface{}) (Page, error) {
+	return p.PaginateIntoContext(context.Background(), c, input, dst)
+}
+
+func (p *Paginator) PaginateIntoContext(ctx context.Context, c cursor.Cursor, input interface{}, dst interface{}) (Page, error) {
+	ip, ok := p.Driver.(driver.IntoPaginator)
+	if !ok {
+		page, err := p.PaginateContext(ctx, c, input)
+		if err != nil {
+			return Page{}, err
+		}
+
+		if err := page.QueryContext(ctx, dst); err != nil {
+			return Page{}, err
+		}
+
+		return page, nil
+	}
+
+	dp, err := ip.PaginateInto(ctx, c, input, dst)
+	if err != nil {
+		return Page{}, err
+	}
+
+	return p.page(ctx, dp)
+}
+
+func (p *Paginator) page(ctx context.Context, dp driver.Page) (Page, error) {
 	info := dp.Info()
 
+	natural := false
+	if n, ok := dp.(driver.NaturalOrderer); ok {
+		natural = n.NaturalOrder()
+	}
+
 	sc, err := p.CursorMarshaller.Marshal(info.StartCursor)
 	if err != nil {
 		return Page{}, err
@@ -89,6 +220,7 @@ func (p *Paginator) Paginate(c cursor.Cursor, input interface{}) (Page, error) {
 			StartCursor:     string(sc),
 			EndCursor:       string(ec),
 		},
+		NaturalOrder: natural,
 		CursorFunc: func(i int64) (string, error) {
 			rc, err := dp.Cursor(i)
 			if err != nil {
@@ -102,5 +234,6 @@ func (p *Paginator) Paginate(c cursor.Cursor, input interface{}) (Page, error) {
 
 			return string(m), nil
 		},
+		ctx: ctx,
 	}, nil
 }
@@ -0,0 +1,206 @@
+// Adapter between go-paginate and the Relay GraphQL Cursor Connections Specification
+// (https://relay.dev/graphql/connections.htm)
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/base"
+	"reflect"
+)
+
+var (
+	ErrFirstAndLast     = errors.New("relay: `first` and `last` cannot be used together")
+	ErrNegativeFirst    = errors.New("relay: `first` must be a non-negative integer")
+	ErrNegativeLast     = errors.New("relay: `last` must be a non-negative integer")
+	ErrMissingFirstLast = errors.New("relay: `first` or `last` must be provided")
+	ErrLimitExceeded    = errors.New("relay: `first` or `last` exceeds the maximum page size")
+	ErrNodesNotASlice   = errors.New("relay: nodes must be a slice")
+)
+
+// Connection arguments, as received from the GraphQL layer
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+type Options struct {
+	// Page size when neither `first` nor `last` are provided, ErrMissingFirstLast is returned when 0
+	DefaultLimit int
+	// Maximum value of `first` and `last`, unlimited when 0
+	MaxLimit int
+}
+
+type Edge struct {
+	Cursor string      `json:"cursor"`
+	Node   interface{} `json:"node"`
+}
+
+type Connection struct {
+	Edges    []Edge               `json:"edges"`
+	PageInfo go_paginate.PageInfo `json:"pageInfo"`
+}
+
+// Validates the arguments and creates the corresponding cursor. When both `after` and `before` are provided,
+// the pagination is restricted to the rows between them, `first` (the default) starting from `after`,
+// and `last` from `before`.
+// The limit of the cursor is 0 for `first: 0` and `last: 0`, which must be paginated with Paginate
+func Cursor(pg *go_paginate.Paginator, args Args, o Options) (cursor.Cursor, error) {
+	after := stringValue(args.After)
+	before := stringValue(args.Before)
+
+	if args.First != nil && args.Last != nil {
+		return cursor.Cursor{}, ErrFirstAndLast
+	}
+
+	typ := cursor.After
+	limit := o.DefaultLimit
+
+	switch {
+	case args.First != nil:
+		if *args.First < 0 {
+			return cursor.Cursor{}, ErrNegativeFirst
+		}
+
+		limit = *args.First
+	case args.Last != nil:
+		if *args.Last < 0 {
+			return cursor.Cursor{}, ErrNegativeLast
+		}
+
+		typ = cursor.Before
+		limit = *args.Last
+	case before != "" && after == "":
+		typ = cursor.Before
+	}
+
+	if args.First == nil && args.Last == nil && limit == 0 {
+		return cursor.Cursor{}, ErrMissingFirstLast
+	}
+
+	if o.MaxLimit > 0 && limit > o.MaxLimit {
+		return cursor.Cursor{}, fmt.Errorf("%w: %v > %v", ErrLimitExceeded, limit, o.MaxLimit)
+	}
+
+	return pg.WindowCursor(after, before, typ, limit)
+}
+
+// Same as pg.PaginateContext, also accepting the cursors of `first: 0` and `last: 0` (see Cursor): their page has no row,
+// its PageInfo tells whether rows remain in the direction of the cursor, found by paginating one row
+func Paginate(ctx context.Context, pg *go_paginate.Paginator, c cursor.Cursor, input interface{}) (go_paginate.Page, error) {
+	if c.Limit != 0 {
+		return pg.PaginateContext(ctx, c, input)
+	}
+
+	c.Limit = 1
+	page, err := pg.PaginateContext(ctx, c, input)
+	if err != nil {
+		return go_paginate.Page{}, err
+	}
+
+	// In the order of the traversal
+	info := go_paginate.PageInfo{
+		HasNextPage:     page.PageInfo.StartCursor != "",
+		HasPreviousPage: page.PageInfo.HasPreviousPage,
+	}
+
+	natural := page.NaturalOrder && c.Type == cursor.Before
+	if natural {
+		info.HasPreviousPage = page.PageInfo.HasNextPage
+		info.HasNextPage, info.HasPreviousPage = info.HasPreviousPage, info.HasNextPage
+	}
+
+	return go_paginate.Page{
+		Executor:     emptyExecutor{page.Executor},
+		PageInfo:     info,
+		NaturalOrder: page.NaturalOrder,
+		CursorFunc: func(i int64) (string, error) {
+			return "", fmt.Errorf("%w: %v", go_paginate.ErrNoCursor, i)
+		},
+	}, nil
+}
+
+// Executor of a page without rows, TotalCount still counts the rows matching the input
+type emptyExecutor struct {
+	executor driver.Executor
+}
+
+func (e emptyExecutor) Query(_ context.Context, dst interface{}) error {
+	base.Empty(dst)
+
+	return nil
+}
+
+func (e emptyExecutor) Count(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (e emptyExecutor) TotalCount(ctx context.Context) (int64, error) {
+	tc, ok := e.executor.(driver.TotalCounter)
+	if !ok {
+		return 0, driver.ErrTotalCountUnsupported
+	}
+
+	return tc.TotalCount(ctx)
+}
+
+// Creates the connection from the page obtained with c and the nodes returned by page.Query,
+// pages of Before cursors are put back in the natural order (unless already in it, see Page.NaturalOrder),
+// as expected by the specification
+func NewConnection(c cursor.Cursor, page go_paginate.Page, nodes interface{}) (Connection, error) {
+	v := reflect.ValueOf(nodes)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return Connection{}, ErrNodesNotASlice
+	}
+
+	edges := make([]Edge, v.Len())
+	for i := range edges {
+		ec, err := page.Cursor(int64(i))
+		if err != nil {
+			return Connection{}, err
+		}
+
+		edges[i] = Edge{
+			Cursor: ec,
+			Node:   v.Index(i).Interface(),
+		}
+	}
+
+	pageInfo := page.PageInfo
+
+	if c.Type == cursor.Before && !page.NaturalOrder {
+		for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+			edges[i], edges[j] = edges[j], edges[i]
+		}
+
+		pageInfo = go_paginate.PageInfo{
+			HasNextPage:     page.PageInfo.HasPreviousPage,
+			HasPreviousPage: page.PageInfo.HasNextPage,
+			StartCursor:     page.PageInfo.EndCursor,
+			EndCursor:       page.PageInfo.StartCursor,
+		}
+	}
+
+	return Connection{
+		Edges:    edges,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
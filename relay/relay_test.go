@@ -0,0 +1,356 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"github.com/raphaelvigee/go-paginate"
+	"github.com/raphaelvigee/go-paginate/cursor"
+	"github.com/raphaelvigee/go-paginate/driver"
+	"github.com/raphaelvigee/go-paginate/driver/slice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type identityEncoder struct{}
+
+func (identityEncoder) CursorEncode(input interface{}) (interface{}, error) {
+	return input, nil
+}
+
+func (identityEncoder) CursorDecode(input interface{}) (interface{}, error) {
+	return input, nil
+}
+
+// Returns the cursors of the nodes as is, in the order given
+type fakeDriver struct {
+	identityEncoder
+	nodes   []string
+	info    driver.PageInfo
+	natural bool
+}
+
+func (d fakeDriver) Paginate(context.Context, cursor.Cursor, interface{}) (driver.Page, error) {
+	return fakePage(d), nil
+}
+
+type fakePage fakeDriver
+
+func (p fakePage) Query(context.Context, interface{}) error {
+	return nil
+}
+
+func (p fakePage) Count(context.Context) (int64, error) {
+	return int64(len(p.nodes)), nil
+}
+
+func (p fakePage) Cursor(i int64) (interface{}, error) {
+	return p.nodes[i], nil
+}
+
+func (p fakePage) Info() driver.PageInfo {
+	return p.info
+}
+
+func (p fakePage) NaturalOrder() bool {
+	return p.natural
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestCursor(t *testing.T) {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: fakeDriver{},
+	})
+
+	encoded, err := pg.CursorMarshaller.Marshal("c")
+	require.NoError(t, err)
+	c := string(encoded)
+
+	encoded, err = pg.CursorMarshaller.Marshal("d")
+	require.NoError(t, err)
+	d := string(encoded)
+
+	tests := []struct {
+		name  string
+		args  Args
+		o     Options
+		typ   cursor.Type
+		limit int
+		value interface{}
+		until interface{}
+		err   error
+	}{
+		{name: "first", args: Args{First: intPtr(2)}, typ: cursor.After, limit: 2},
+		{name: "first after", args: Args{First: intPtr(2), After: &c}, typ: cursor.After, limit: 2, value: "c"},
+		{name: "last", args: Args{Last: intPtr(3)}, typ: cursor.Before, limit: 3},
+		{name: "last before", args: Args{Last: intPtr(3), Before: &c}, typ: cursor.Before, limit: 3, value: "c"},
+		{name: "default", args: Args{}, o: Options{DefaultLimit: 10}, typ: cursor.After, limit: 10},
+		{name: "default before", args: Args{Before: &c}, o: Options{DefaultLimit: 10}, typ: cursor.Before, limit: 10, value: "c"},
+		{name: "empty after", args: Args{First: intPtr(2), After: stringPtr("")}, typ: cursor.After, limit: 2},
+		{name: "missing", args: Args{}, err: ErrMissingFirstLast},
+		{name: "first last", args: Args{First: intPtr(1), Last: intPtr(1)}, err: ErrFirstAndLast},
+		{name: "after before", args: Args{After: &c, Before: &d}, o: Options{DefaultLimit: 10}, typ: cursor.After, limit: 10, value: "c", until: "d"},
+		{name: "first after before", args: Args{First: intPtr(1), After: &c, Before: &d}, typ: cursor.After, limit: 1, value: "c", until: "d"},
+		{name: "last after before", args: Args{Last: intPtr(1), After: &c, Before: &d}, typ: cursor.Before, limit: 1, value: "d", until: "c"},
+		{name: "first before", args: Args{First: intPtr(1), Before: &c}, typ: cursor.After, limit: 1, until: "c"},
+		{name: "last after", args: Args{Last: intPtr(1), After: &c}, typ: cursor.Before, limit: 1, until: "c"},
+		{name: "negative first", args: Args{First: intPtr(-1)}, err: ErrNegativeFirst},
+		{name: "negative last", args: Args{Last: intPtr(-1)}, err: ErrNegativeLast},
+		{name: "zero first", args: Args{First: intPtr(0)}, typ: cursor.After, limit: 0},
+		{name: "zero last", args: Args{Last: intPtr(0), Before: &c}, typ: cursor.Before, limit: 0, value: "c"},
+		{name: "max", args: Args{First: intPtr(11)}, o: Options{MaxLimit: 10}, err: ErrLimitExceeded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			csr, err := Cursor(pg, test.args, test.o)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.typ, csr.Type)
+			assert.Equal(t, test.limit, csr.Limit)
+			assert.Equal(t, test.value, csr.Value)
+			assert.Equal(t, test.until, csr.Until)
+		})
+	}
+}
+
+func testConnection(t *testing.T, typ cursor.Type, natural bool, info driver.PageInfo) Connection {
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: fakeDriver{
+			nodes:   []string{"a", "b", "c"},
+			info:    info,
+			natural: natural,
+		},
+	})
+
+	c, err := pg.Cursor("", typ, 3)
+	require.NoError(t, err)
+
+	page, err := pg.Paginate(c, nil)
+	require.NoError(t, err)
+
+	conn, err := NewConnection(c, page, []string{"A", "B", "C"})
+	require.NoError(t, err)
+
+	return conn
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	pg := go_paginate.New(go_paginate.Options{})
+
+	encoded, err := pg.CursorMarshaller.Marshal(v)
+	require.NoError(t, err)
+
+	return string(encoded)
+}
+
+func TestNewConnection_After(t *testing.T) {
+	conn := testConnection(t, cursor.After, false, driver.PageInfo{
+		HasPreviousPage: false,
+		HasNextPage:     true,
+		StartCursor:     "a",
+		EndCursor:       "c",
+	})
+
+	assert.Equal(t, []Edge{
+		{Cursor: marshal(t, "a"), Node: "A"},
+		{Cursor: marshal(t, "b"), Node: "B"},
+		{Cursor: marshal(t, "c"), Node: "C"},
+	}, conn.Edges)
+	assert.Equal(t, go_paginate.PageInfo{
+		HasPreviousPage: false,
+		HasNextPage:     true,
+		StartCursor:     marshal(t, "a"),
+		EndCursor:       marshal(t, "c"),
+	}, conn.PageInfo)
+}
+
+func TestNewConnection_Before(t *testing.T) {
+	conn := testConnection(t, cursor.Before, false, driver.PageInfo{
+		HasPreviousPage: false,
+		HasNextPage:     true,
+		StartCursor:     "a",
+		EndCursor:       "c",
+	})
+
+	assert.Equal(t, []Edge{
+		{Cursor: marshal(t, "c"), Node: "C"},
+		{Cursor: marshal(t, "b"), Node: "B"},
+		{Cursor: marshal(t, "a"), Node: "A"},
+	}, conn.Edges)
+	assert.Equal(t, go_paginate.PageInfo{
+		HasPreviousPage: true,
+		HasNextPage:     false,
+		StartCursor:     marshal(t, "c"),
+		EndCursor:       marshal(t, "a"),
+	}, conn.PageInfo)
+}
+
+func TestNewConnection_BeforeNaturalOrder(t *testing.T) {
+	conn := testConnection(t, cursor.Before, true, driver.PageInfo{
+		HasPreviousPage: true,
+		HasNextPage:     false,
+		StartCursor:     "a",
+		EndCursor:       "c",
+	})
+
+	assert.Equal(t, []Edge{
+		{Cursor: marshal(t, "a"), Node: "A"},
+		{Cursor: marshal(t, "b"), Node: "B"},
+		{Cursor: marshal(t, "c"), Node: "C"},
+	}, conn.Edges)
+	assert.Equal(t, go_paginate.PageInfo{
+		HasPreviousPage: true,
+		HasNextPage:     false,
+		StartCursor:     marshal(t, "a"),
+		EndCursor:       marshal(t, "c"),
+	}, conn.PageInfo)
+}
+
+func TestNewConnection_NotASlice(t *testing.T) {
+	_, err := NewConnection(cursor.Cursor{}, go_paginate.Page{}, "nope")
+	assert.Equal(t, ErrNodesNotASlice, err)
+}
+
+type user struct {
+	Name      string
+	CreatedAt int
+}
+
+func TestConnection_Slice(t *testing.T) {
+	users := []user{{"u1", 4}, {"u2", 10}, {"u3", 1}, {"u4", 6}}
+
+	pg := go_paginate.New(go_paginate.Options{
+		Driver: slice.New(slice.Options{
+			Keys: []slice.Key{{
+				Value: func(v interface{}) interface{} {
+					return v.(user).CreatedAt
+				},
+			}},
+		}),
+	})
+
+	connection := func(args Args) Connection {
+		c, err := Cursor(pg, args, Options{MaxLimit: 10})
+		require.NoError(t, err)
+
+		page, err := pg.Paginate(c, users)
+		require.NoError(t, err)
+
+		var nodes []user
+		require.NoError(t, page.Query(&nodes))
+
+		conn, err := NewConnection(c, page, nodes)
+		require.NoError(t, err)
+
+		return conn
+	}
+
+	names := func(conn Connection) []string {
+		names := make([]string, 0)
+		for _, edge := range conn.Edges {
+			names = append(names, edge.Node.(user).Name)
+		}
+
+		return names
+	}
+
+	conn := connection(Args{First: intPtr(2)})
+	assert.Equal(t, []string{"u3", "u1"}, names(conn))
+	assert.False(t, conn.PageInfo.HasPreviousPage)
+	assert.True(t, conn.PageInfo.HasNextPage)
+	assert.Equal(t, conn.Edges[1].Cursor, conn.PageInfo.EndCursor)
+
+	conn = connection(Args{First: intPtr(2), After: &conn.PageInfo.EndCursor})
+	assert.Equal(t, []string{"u4", "u2"}, names(conn))
+	assert.False(t, conn.PageInfo.HasNextPage)
+
+	// Back to the first page, in the natural order
+	conn = connection(Args{Last: intPtr(2), Before: &conn.PageInfo.StartCursor})
+	assert.Equal(t, []string{"u3", "u1"}, names(conn))
+	assert.False(t, conn.PageInfo.HasPreviousPage)
+	assert.Equal(t, conn.Edges[0].Cursor, conn.PageInfo.StartCursor)
+
+}
+
+func TestConnection_Zero(t *testing.T) {
+	users := []user{{"u1", 4}, {"u2", 10}, {"u3", 1}, {"u4", 6}}
+
+	for _, natural := range []bool{false, true} {
+		pg := go_paginate.New(go_paginate.Options{
+			Driver: slice.New(slice.Options{
+				Keys: []slice.Key{{
+					Value: func(v interface{}) interface{} {
+						return v.(user).CreatedAt
+					},
+				}},
+				NaturalOrder: natural,
+			}),
+		})
+
+		connection := func(args Args) Connection {
+			c, err := Cursor(pg, args, Options{})
+			require.NoError(t, err)
+
+			page, err := Paginate(context.Background(), pg, c, users)
+			require.NoError(t, err)
+
+			nodes := []user{users[0]}
+			require.NoError(t, page.Query(&nodes))
+			assert.Empty(t, nodes)
+
+			count, err := page.Count()
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), count)
+
+			total, err := page.TotalCount()
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), total)
+
+			_, err = page.Cursor(0)
+			assert.True(t, errors.Is(err, go_paginate.ErrNoCursor))
+
+			conn, err := NewConnection(c, page, nodes)
+			require.NoError(t, err)
+			assert.Empty(t, conn.Edges)
+			assert.Empty(t, conn.PageInfo.StartCursor)
+			assert.Empty(t, conn.PageInfo.EndCursor)
+
+			return conn
+		}
+
+		conn := connection(Args{First: intPtr(0)})
+		assert.False(t, conn.PageInfo.HasPreviousPage)
+		assert.True(t, conn.PageInfo.HasNextPage)
+
+		conn = connection(Args{Last: intPtr(0)})
+		assert.True(t, conn.PageInfo.HasPreviousPage)
+		assert.False(t, conn.PageInfo.HasNextPage)
+
+		c, err := Cursor(pg, Args{First: intPtr(4)}, Options{})
+		require.NoError(t, err)
+		page, err := Paginate(context.Background(), pg, c, users)
+		require.NoError(t, err)
+
+		// No row after the last one
+		conn = connection(Args{First: intPtr(0), After: &page.PageInfo.EndCursor})
+		assert.True(t, conn.PageInfo.HasPreviousPage)
+		assert.False(t, conn.PageInfo.HasNextPage)
+
+		// Nor before the first one
+		conn = connection(Args{Last: intPtr(0), Before: &page.PageInfo.StartCursor})
+		assert.False(t, conn.PageInfo.HasPreviousPage)
+		assert.True(t, conn.PageInfo.HasNextPage)
+	}
+}
@@ -0,0 +1,61 @@
+package go_paginate
+
+import (
+	"context"
+	"github.com/raphaelvigee/go-paginate/cursor"
+)
+
+// Typed layer over a Paginator, the rows of the pages are scanned into a []T
+type TypedPaginator[T any] struct {
+	*Paginator
+}
+
+func For[T any](p *Paginator) TypedPaginator[T] {
+	return TypedPaginator[T]{p}
+}
+
+type Edge[T any] struct {
+	Cursor string `json:"cursor"`
+	Node   T      `json:"node"`
+}
+
+type TypedPage[T any] struct {
+	Page
+
+	// The rows of the page, in the same order as Page.Query
+	Items []T
+	// The rows along with their cursors
+	Edges []Edge[T]
+}
+
+// Paginates and scans the rows of the page, see Paginator.PaginateIntoContext
+func (p TypedPaginator[T]) Paginate(ctx context.Context, c cursor.Cursor, input interface{}) (TypedPage[T], error) {
+	var items []T
+	page, err := p.PaginateIntoContext(ctx, c, input, &items)
+	if err != nil {
+		return TypedPage[T]{}, err
+	}
+
+	if items == nil {
+		items = []T{}
+	}
+
+	edges := make([]Edge[T], len(items))
+	for i, item := range items {
+		ec, err := page.Cursor(int64(i))
+		if err != nil {
+			return TypedPage[T]{}, err
+		}
+
+		edges[i] = Edge[T]{
+			Cursor: ec,
+			Node:   item,
+		}
+	}
+
+	return TypedPage[T]{
+		Page:  page,
+		Items: items,
+		Edges: edges,
+	}, nil
+}